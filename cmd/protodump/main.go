@@ -0,0 +1,313 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// protodump converts protocol buffer messages between the binary wire
+// format and the text or JSON formats.
+//
+// Usage:
+//
+//	protodump [-descriptor_set=FILE -message=NAME] [flags] [path]
+//
+// By default, protodump reads binary messages from the named file, or from
+// standard input without an explicit path, and prints them in the text
+// format. The message type is given by -message, and is looked up among the
+// types declared in the FileDescriptorSet given by -descriptor_set (as
+// produced by protoc --descriptor_set_out --include_imports) and those
+// linked into protodump, such as the well-known types.
+// Without -message, messages are decoded without a schema: fields are
+// identified by number, and the type of each length-delimited field is
+// guessed from its contents. Messages that lack required fields are
+// still printed.
+//
+// The flags are:
+//
+//	-delimited
+//		The input is a stream of messages, each prefixed by its length
+//		as a varint. With -encode, the output is such a stream.
+//	-encoding=binary|base64|hex
+//		The encoding of the binary input. With -encode, the encoding of
+//		the binary output. Whitespace in base64 and hex input is ignored.
+//	-format=text|json
+//		The format of the output. With -encode, the format of the input.
+//	-encode
+//		Convert in the reverse direction: read a message in the format
+//		given by -format and write it in the binary wire format.
+//		It requires -message. With -format=json and -delimited,
+//		the input may hold a sequence of JSON objects.
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/golang/protobuf/internal/descset"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+var (
+	descriptorSet = flag.String("descriptor_set", "", "path to a serialized FileDescriptorSet")
+	messageName   = flag.String("message", "", "full name of the message type (e.g., my.pkg.Config); decode without a schema if empty")
+	delimited     = flag.Bool("delimited", false, "binary data is a stream of length-delimited messages")
+	encoding      = flag.String("encoding", "binary", "encoding of binary data: binary, base64, or hex")
+	format        = flag.String("format", "text", "format of decoded messages: text or json")
+	encode        = flag.Bool("encode", false, "encode text or JSON input to binary instead of decoding")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: protodump [-descriptor_set=FILE -message=NAME] [flags] [path]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() > 1 {
+		usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "protodump: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run converts the messages in the named file, or standard input if path
+// is empty, and writes the result to out.
+func run(path string, out io.Writer) error {
+	switch *encoding {
+	case "binary", "base64", "hex":
+	default:
+		return fmt.Errorf("unknown -encoding %q", *encoding)
+	}
+	switch *format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unknown -format %q", *format)
+	}
+	if *encode && *messageName == "" {
+		return errors.New("-encode requires -message")
+	}
+	if *descriptorSet != "" {
+		if _, err := descset.Load(*descriptorSet); err != nil {
+			return err
+		}
+	}
+	if *messageName != "" {
+		if _, err := descset.NewMessage(*messageName); err != nil {
+			return err
+		}
+	}
+
+	in := io.Reader(os.Stdin)
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	src, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	var res []byte
+	if *encode {
+		res, err = encodeMessages(src)
+	} else {
+		res, err = decodeMessages(src)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(res)
+	return err
+}
+
+// decodeMessages decodes the binary messages in src
+// and returns them in the output format.
+func decodeMessages(src []byte) ([]byte, error) {
+	b, err := decodeBinary(src)
+	if err != nil {
+		return nil, err
+	}
+	if !*delimited {
+		return formatMessage(b)
+	}
+
+	var out []byte
+	for i := 0; len(b) > 0; i++ {
+		n, k := proto.DecodeVarint(b)
+		if k == 0 || uint64(len(b)-k) < n {
+			return out, fmt.Errorf("message %d: invalid length prefix", i)
+		}
+		res, err := formatMessage(b[k : k+int(n)])
+		if err != nil {
+			return out, fmt.Errorf("message %d: %v", i, err)
+		}
+		if *format == "text" {
+			out = append(out, fmt.Sprintf("# message %d\n", i)...)
+		}
+		out = append(out, res...)
+		b = b[k+int(n):]
+	}
+	return out, nil
+}
+
+// formatMessage returns the binary message b in the output format.
+func formatMessage(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if *messageName == "" {
+		m, err := proto.DecodeWire(b)
+		if err != nil {
+			return nil, err
+		}
+		if *format == "json" {
+			res, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			return append(res, '\n'), nil
+		}
+		return []byte(m.String()), nil
+	}
+
+	m, err := descset.NewMessage(*messageName)
+	if err != nil {
+		return nil, err
+	}
+	// Print messages with missing required fields, since they are
+	// otherwise well-formed.
+	var rnse *proto.RequiredNotSetError
+	if err := proto.Unmarshal(b, m); err != nil && !errors.As(err, &rnse) {
+		return nil, err
+	}
+	if *format == "json" {
+		if err := (&jsonpb.Marshaler{Indent: "  "}).Marshal(&buf, m); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	}
+	if err := (&proto.TextMarshaler{ExpandAny: true}).Marshal(&buf, m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeMessages parses the messages in src in the input format
+// and returns their binary encoding.
+func encodeMessages(src []byte) ([]byte, error) {
+	var msgs []proto.Message
+	if *format == "json" {
+		d := json.NewDecoder(bytes.NewReader(src))
+		for {
+			m, err := descset.NewMessage(*messageName)
+			if err != nil {
+				return nil, err
+			}
+			err = jsonpb.UnmarshalNext(d, m)
+			if err == io.EOF {
+				if len(msgs) == 0 {
+					return nil, errors.New("empty input")
+				}
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, m)
+			if !*delimited {
+				if d.More() {
+					return nil, errors.New("unexpected data after JSON object; use -delimited to encode multiple messages")
+				}
+				break
+			}
+		}
+	} else {
+		m, err := descset.NewMessage(*messageName)
+		if err != nil {
+			return nil, err
+		}
+		if err := proto.UnmarshalText(string(src), m); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+
+	// Dynamic messages are otherwise encoded with their fields in no
+	// particular order.
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	for _, m := range msgs {
+		var err error
+		if *delimited {
+			err = buf.EncodeMessage(m)
+		} else {
+			err = buf.Marshal(m)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return encodeBinary(buf.Bytes()), nil
+}
+
+// decodeBinary decodes src according to -encoding.
+func decodeBinary(src []byte) ([]byte, error) {
+	switch *encoding {
+	case "base64":
+		s := stripSpace(src)
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			// Also accept input without padding.
+			if b, err2 := base64.RawStdEncoding.DecodeString(s); err2 == nil {
+				return b, nil
+			}
+			return nil, fmt.Errorf("invalid base64 input: %v", err)
+		}
+		return b, nil
+	case "hex":
+		b, err := hex.DecodeString(stripSpace(src))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex input: %v", err)
+		}
+		return b, nil
+	default:
+		return src, nil
+	}
+}
+
+// encodeBinary encodes b according to -encoding.
+func encodeBinary(b []byte) []byte {
+	switch *encoding {
+	case "base64":
+		return []byte(base64.StdEncoding.EncodeToString(b) + "\n")
+	case "hex":
+		return []byte(hex.EncodeToString(b) + "\n")
+	default:
+		return b
+	}
+}
+
+func stripSpace(b []byte) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(b))
+}
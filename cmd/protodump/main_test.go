@@ -0,0 +1,196 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/internal/descset"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// configTypeName is the message type used by the tests.
+const configTypeName = "protodump.test.Config"
+
+func init() {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("protodump_test.proto"),
+		Package: proto.String("protodump.test"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Config"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("name"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				{Name: proto.String("ports"), Number: proto.Int32(2), Label: repeated, Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+				{Name: proto.String("server"), Number: proto.Int32(3), Label: repeated, Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".protodump.test.Server")},
+			},
+		}, {
+			Name: proto.String("Server"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("host"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				{Name: proto.String("port"), Number: proto.Int32(2), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+			},
+		}},
+	}}}
+	b, err := proto.Marshal(fds)
+	if err != nil {
+		panic(err)
+	}
+	dir, err := ioutil.TempDir("", "protodump")
+	if err != nil {
+		panic(err)
+	}
+	name := filepath.Join(dir, "config.pb")
+	if err := ioutil.WriteFile(name, b, 0644); err != nil {
+		panic(err)
+	}
+	if _, err := descset.Load(name); err != nil {
+		panic(err)
+	}
+}
+
+// setFlags sets the named flags for the duration of the test.
+func setFlags(t *testing.T, flags map[string]string) {
+	for name, value := range flags {
+		if err := flag.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		for name := range flags {
+			f := flag.Lookup(name)
+			f.Value.Set(f.DefValue)
+		}
+	})
+}
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		desc   string
+		flags  map[string]string
+		input  string
+		golden string
+	}{{
+		desc:   "binary to text",
+		flags:  map[string]string{"message": configTypeName},
+		input:  "config.bin",
+		golden: "config.textproto",
+	}, {
+		desc:   "hex to text",
+		flags:  map[string]string{"message": configTypeName, "encoding": "hex"},
+		input:  "config.hex",
+		golden: "config.textproto",
+	}, {
+		desc:   "base64 to JSON",
+		flags:  map[string]string{"message": configTypeName, "encoding": "base64", "format": "json"},
+		input:  "config.base64",
+		golden: "config.json",
+	}, {
+		desc:   "delimited hex to text",
+		flags:  map[string]string{"message": configTypeName, "encoding": "hex", "delimited": "true"},
+		input:  "delimited.hex",
+		golden: "delimited.textproto",
+	}, {
+		desc:   "binary without schema",
+		flags:  map[string]string{},
+		input:  "config.bin",
+		golden: "config.wire",
+	}, {
+		desc:   "text to binary",
+		flags:  map[string]string{"message": configTypeName, "encode": "true"},
+		input:  "config.textproto",
+		golden: "config.bin",
+	}, {
+		desc:   "JSON to base64",
+		flags:  map[string]string{"message": configTypeName, "encode": "true", "format": "json", "encoding": "base64"},
+		input:  "config.json",
+		golden: "config.base64",
+	}, {
+		desc:   "delimited JSON to hex",
+		flags:  map[string]string{"message": configTypeName, "encode": "true", "format": "json", "encoding": "hex", "delimited": "true"},
+		input:  "delimited.json",
+		golden: "delimited.hex",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			setFlags(t, tt.flags)
+			want, err := ioutil.ReadFile(filepath.Join("testdata", tt.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if err := run(filepath.Join("testdata", tt.input), &out); err != nil {
+				t.Fatalf("run error: %v", err)
+			}
+			if got := out.Bytes(); !bytes.Equal(got, want) {
+				t.Errorf("output mismatch:\ngot:\n%q\nwant (%v):\n%q", got, tt.golden, want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	if err := ioutil.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(dir, "truncated")
+	if err := ioutil.WriteFile(truncated, []byte("\x05\x08"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		desc    string
+		flags   map[string]string
+		input   string
+		wantErr string
+	}{{
+		desc:    "empty JSON input",
+		flags:   map[string]string{"message": configTypeName, "encode": "true", "format": "json"},
+		input:   empty,
+		wantErr: "empty input",
+	}, {
+		desc:    "encode without message",
+		flags:   map[string]string{"encode": "true"},
+		input:   empty,
+		wantErr: "-encode requires -message",
+	}, {
+		desc:    "unknown encoding",
+		flags:   map[string]string{"encoding": "base32"},
+		input:   empty,
+		wantErr: `unknown -encoding "base32"`,
+	}, {
+		desc:    "invalid hex",
+		flags:   map[string]string{"message": configTypeName, "encoding": "hex"},
+		input:   filepath.Join("testdata", "config.base64"),
+		wantErr: "invalid hex input",
+	}, {
+		desc:    "truncated delimited input",
+		flags:   map[string]string{"message": configTypeName, "delimited": "true"},
+		input:   truncated,
+		wantErr: "message 0: invalid length prefix",
+	}, {
+		desc:    "missing file",
+		flags:   map[string]string{},
+		input:   filepath.Join("testdata", "missing"),
+		wantErr: "no such file",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			setFlags(t, tt.flags)
+			err := run(tt.input, new(bytes.Buffer))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("run error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// textprotofmt formats and validates protocol buffer text format files.
+//
+// Usage:
+//
+//	textprotofmt -descriptor_set=FILE -message=NAME [flags] [path ...]
+//
+// Each input file is parsed as a text format encoding of the named message,
+// using the message and extension types declared in the FileDescriptorSet
+// (as produced by protoc --descriptor_set_out --include_imports).
+// Without an explicit path, it reads from standard input.
+//
+// Parsing fails on unknown field names, unknown enum value names,
+// malformed values, and missing required fields; each error is reported as
+// "path:line:column: message". By default, the canonically formatted text is
+// written to standard output. The flags are:
+//
+//	-d
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different than textprotofmt's, print diffs
+//		to standard output.
+//	-l
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different from textprotofmt's, print its name
+//		to standard output.
+//	-w
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different from textprotofmt's, overwrite it
+//		with textprotofmt's version.
+//
+// The canonical format is the one produced by proto.MarshalText.
+// Comments in the input are not preserved.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/golang/protobuf/internal/descset"
+	"github.com/golang/protobuf/proto"
+)
+
+var (
+	descriptorSet = flag.String("descriptor_set", "", "path to a serialized FileDescriptorSet")
+	messageName   = flag.String("message", "", "full name of the message type of each input (e.g., my.pkg.Config)")
+	doDiff        = flag.Bool("d", false, "display diffs instead of rewriting files")
+	list          = flag.Bool("l", false, "list files whose formatting differs from textprotofmt's")
+	write         = flag.Bool("w", false, "write result to (source) file instead of stdout")
+)
+
+var exitCode = 0
+
+func report(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	exitCode = 2
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: textprotofmt -descriptor_set=FILE -message=NAME [flags] [path ...]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if *descriptorSet == "" || *messageName == "" {
+		usage()
+		os.Exit(2)
+	}
+	if _, err := descset.Load(*descriptorSet); err != nil {
+		fmt.Fprintf(os.Stderr, "textprotofmt: %v\n", err)
+		os.Exit(2)
+	}
+	if _, err := descset.NewMessage(*messageName); err != nil {
+		fmt.Fprintf(os.Stderr, "textprotofmt: %v\n", err)
+		os.Exit(2)
+	}
+
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "textprotofmt: cannot use -w with standard input")
+			os.Exit(2)
+		}
+		if err := processFile("<standard input>", os.Stdin, os.Stdout); err != nil {
+			report(err)
+		}
+		os.Exit(exitCode)
+	}
+	for _, path := range flag.Args() {
+		if err := processFile(path, nil, os.Stdout); err != nil {
+			report(err)
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// processFile formats the named file, reading it from in if non-nil.
+func processFile(filename string, in io.Reader, out io.Writer) error {
+	var perm os.FileMode = 0644
+	if in == nil {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		perm = fi.Mode().Perm()
+		in = f
+	}
+
+	src, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	res, err := format(filename, src)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(src, res) {
+		if *list {
+			fmt.Fprintln(out, filename)
+		}
+		if *write {
+			if err := ioutil.WriteFile(filename, res, perm); err != nil {
+				return err
+			}
+		}
+		if *doDiff {
+			d, err := diff(src, res, filename)
+			if err != nil {
+				return fmt.Errorf("computing diff: %s", err)
+			}
+			fmt.Fprintf(out, "diff -u %s.orig %s\n", filename, filename)
+			out.Write(d)
+		}
+	}
+	if !*list && !*write && !*doDiff {
+		_, err = out.Write(res)
+	}
+	return err
+}
+
+// format parses src as the text format of the message named by -message
+// and returns its canonical text format.
+func format(filename string, src []byte) ([]byte, error) {
+	m, err := descset.NewMessage(*messageName)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.UnmarshalText(string(src), m); err != nil {
+		var pe *proto.ParseError
+		if errors.As(err, &pe) {
+			if pe.Path != "" {
+				return nil, fmt.Errorf("%s:%d:%d: %s (in field %s)", filename, pe.Line, pe.Column, pe.Message, pe.Path)
+			}
+			return nil, fmt.Errorf("%s:%d:%d: %s", filename, pe.Line, pe.Column, pe.Message)
+		}
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	var buf bytes.Buffer
+	if err := proto.MarshalText(&buf, m); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeTempFile(dir, prefix string, data []byte) (string, error) {
+	file, err := ioutil.TempFile(dir, prefix)
+	if err != nil {
+		return "", err
+	}
+	_, err = file.Write(data)
+	if err1 := file.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func diff(b1, b2 []byte, filename string) ([]byte, error) {
+	f1, err := writeTempFile("", "textprotofmt", b1)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f1)
+
+	f2, err := writeTempFile("", "textprotofmt", b2)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f2)
+
+	data, err := exec.Command("diff", "-u", "--label", filename+".orig", "--label", filename, f1, f2).CombinedOutput()
+	if len(data) > 0 {
+		// diff exits with a non-zero status when the files don't match.
+		// Ignore that failure as long as we get output.
+		return data, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/internal/descset"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// loadConfigType registers the message type textprotofmt.test.Config
+// and selects it with the -message flag.
+func loadConfigType(t *testing.T) {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("textprotofmt_test.proto"),
+		Package: proto.String("textprotofmt.test"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Config"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("name"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				{Name: proto.String("ports"), Number: proto.Int32(2), Label: repeated, Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+				{Name: proto.String("server"), Number: proto.Int32(3), Label: repeated, Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".textprotofmt.test.Server")},
+				{Name: proto.String("mode"), Number: proto.Int32(4), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(), TypeName: proto.String(".textprotofmt.test.Mode")},
+			},
+		}, {
+			Name: proto.String("Server"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("host"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				{Name: proto.String("port"), Number: proto.Int32(2), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+			},
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Mode"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("DEVELOPMENT"), Number: proto.Int32(0)},
+				{Name: proto.String("PRODUCTION"), Number: proto.Int32(1)},
+			},
+		}},
+	}}}
+	b, err := proto.Marshal(fds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "config.pb")
+	if err := ioutil.WriteFile(name, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := descset.Load(name); err != nil {
+		t.Fatal(err)
+	}
+	*messageName = "textprotofmt.test.Config"
+}
+
+func TestFormat(t *testing.T) {
+	loadConfigType(t)
+	want, err := ioutil.ReadFile("testdata/config.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"testdata/config.textproto", "testdata/config.golden"} {
+		var out bytes.Buffer
+		if err := processFile(path, nil, &out); err != nil {
+			t.Fatalf("processFile(%q) error: %v", path, err)
+		}
+		if got := out.String(); got != string(want) {
+			t.Errorf("processFile(%q):\ngot:\n%s\nwant:\n%s", path, got, want)
+		}
+	}
+}
+
+func TestFormatList(t *testing.T) {
+	loadConfigType(t)
+	defer func() { *list = false }()
+	*list = true
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{path: "testdata/config.textproto", want: "testdata/config.textproto\n"},
+		{path: "testdata/config.golden", want: ""},
+	} {
+		var out bytes.Buffer
+		if err := processFile(tt.path, nil, &out); err != nil {
+			t.Fatalf("processFile(%q) error: %v", tt.path, err)
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("processFile(%q) with -l = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	loadConfigType(t)
+	err := processFile("testdata/invalid.textproto", nil, new(bytes.Buffer))
+	const want = `testdata/invalid.textproto:2:10: unknown field name "hots" in textprotofmt.test.Server (in field server[0])`
+	if err == nil || err.Error() != want {
+		t.Errorf("processFile error = %v, want %v", err, want)
+	}
+}
+
+func TestFormatDiff(t *testing.T) {
+	if _, err := exec.LookPath("diff"); err != nil {
+		t.Skipf("diff command not found: %v", err)
+	}
+	loadConfigType(t)
+	defer func() { *doDiff = false }()
+	*doDiff = true
+	want, err := ioutil.ReadFile("testdata/config.diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{path: "testdata/config.textproto", want: string(want)},
+		{path: "testdata/config.golden", want: ""},
+	} {
+		var out bytes.Buffer
+		if err := processFile(tt.path, nil, &out); err != nil {
+			t.Fatalf("processFile(%q) error: %v", tt.path, err)
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("processFile(%q) with -d:\ngot:\n%s\nwant:\n%s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWrite(t *testing.T) {
+	loadConfigType(t)
+	defer func() { *write = false }()
+	*write = true
+	src, err := ioutil.ReadFile("testdata/config.textproto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ioutil.ReadFile("testdata/config.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.textproto")
+	if err := ioutil.WriteFile(path, src, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The second run leaves the formatted file as is.
+	for i := 0; i < 2; i++ {
+		var out bytes.Buffer
+		if err := processFile(path, nil, &out); err != nil {
+			t.Fatalf("processFile(%q) error: %v", path, err)
+		}
+		if out.Len() > 0 {
+			t.Errorf("processFile(%q) with -w wrote %q to the output, want nothing", path, out.Bytes())
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("processFile(%q) with -w wrote:\n%s\nwant:\n%s", path, got, want)
+		}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("processFile(%q) with -w changed the permissions to %v, want %v", path, perm, os.FileMode(0600))
+	}
+
+	// Files that do not parse are not rewritten.
+	bad := filepath.Join(t.TempDir(), "invalid.textproto")
+	invalid, err := ioutil.ReadFile("testdata/invalid.textproto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(bad, invalid, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := processFile(bad, nil, new(bytes.Buffer)); err == nil {
+		t.Errorf("processFile(%q) with -w succeeded, want error", bad)
+	}
+	if got, err := ioutil.ReadFile(bad); err != nil || !bytes.Equal(got, invalid) {
+		t.Errorf("processFile(%q) with -w modified the file: %q, %v", bad, got, err)
+	}
+}
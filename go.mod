@@ -5,5 +5,5 @@ go 1.17
 
 require (
 	github.com/google/go-cmp v0.5.5
-	google.golang.org/protobuf v1.33.0
+	google.golang.org/protobuf v1.34.2
 )
@@ -0,0 +1,74 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package descset loads serialized FileDescriptorSets for use by the
+// command-line tools in this module.
+package descset
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Load reads the FileDescriptorSet in the named file, as produced by
+// protoc --descriptor_set_out --include_imports, and registers every message
+// and extension type it declares in protoregistry.GlobalTypes.
+// Types that are already registered (e.g., well-known types linked into
+// the binary) are left untouched.
+//
+// Registering the types globally allows the text and JSON codecs
+// to resolve extension names and google.protobuf.Any type URLs.
+func Load(name string) (*protoregistry.Files, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	fds := new(descriptorpb.FileDescriptorSet)
+	if err := proto.Unmarshal(b, fds); err != nil {
+		return nil, fmt.Errorf("%s: invalid FileDescriptorSet: %v", name, err)
+	}
+	files, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		registerTypes(fd.Messages(), fd.Extensions())
+		return true
+	})
+	return files, nil
+}
+
+func registerTypes(mds protoreflect.MessageDescriptors, xds protoreflect.ExtensionDescriptors) {
+	for i := 0; i < mds.Len(); i++ {
+		md := mds.Get(i)
+		if _, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err != nil {
+			protoregistry.GlobalTypes.RegisterMessage(dynamicpb.NewMessageType(md))
+		}
+		registerTypes(md.Messages(), md.Extensions())
+	}
+	for i := 0; i < xds.Len(); i++ {
+		xd := xds.Get(i)
+		if _, err := protoregistry.GlobalTypes.FindExtensionByName(xd.FullName()); err != nil {
+			protoregistry.GlobalTypes.RegisterExtension(dynamicpb.NewExtensionType(xd))
+		}
+	}
+}
+
+// NewMessage returns a new, empty message of the named type.
+// The type must have been registered, either by linking in its generated
+// Go package or by calling Load.
+func NewMessage(name string) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("message type %q: %v", name, err)
+	}
+	return proto.MessageV1(mt.New().Interface()), nil
+}
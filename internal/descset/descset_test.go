@@ -0,0 +1,188 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package descset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// writeFile writes b to a file in a temporary directory and returns its name.
+func writeFile(t *testing.T, b []byte) string {
+	name := filepath.Join(t.TempDir(), "set.pb")
+	if err := ioutil.WriteFile(name, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func marshalSet(t *testing.T, files ...*descriptorpb.FileDescriptorProto) []byte {
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: files})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// testFile returns a file in package pkg declaring the message Outer,
+// with a nested message Inner, and an extension of Outer.
+func testFile(pkg string) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String(pkg + ".proto"),
+		Package:    proto.String(pkg),
+		Dependency: []string{"google/protobuf/duration.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Outer"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("timeout"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: proto.String(".google.protobuf.Duration"),
+			}},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Inner"),
+			}},
+			ExtensionRange: []*descriptorpb.DescriptorProto_ExtensionRange{{
+				Start: proto.Int32(100),
+				End:   proto.Int32(200),
+			}},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("ext"),
+			Number:   proto.Int32(100),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String("." + pkg + ".Outer"),
+		}},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	durationFile := protodesc.ToFileDescriptorProto(durationpb.File_google_protobuf_duration_proto)
+	tests := []struct {
+		desc     string
+		pkg      string // package of the types that must be registered
+		data     []byte
+		wantErr  string
+		wantFile string
+	}{{
+		desc:     "types",
+		pkg:      "descset.types",
+		data:     marshalSet(t, durationFile, testFile("descset.types")),
+		wantFile: "descset.types.proto",
+	}, {
+		desc:    "missing dependency",
+		pkg:     "descset.missing",
+		data:    marshalSet(t, testFile("descset.missing")),
+		wantErr: "google/protobuf/duration.proto",
+	}, {
+		desc:    "invalid data",
+		data:    []byte("\x0a\x05abc"),
+		wantErr: "invalid FileDescriptorSet",
+	}, {
+		desc:     "empty",
+		data:     nil,
+		wantFile: "",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			files, err := Load(writeFile(t, tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want error containing %q", err, tt.wantErr)
+				}
+				if tt.pkg != "" {
+					if _, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(tt.pkg + ".Outer")); err == nil {
+						t.Errorf("Load registered %v.Outer despite an error", tt.pkg)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load error: %v", err)
+			}
+			if tt.wantFile == "" {
+				if n := files.NumFiles(); n != 0 {
+					t.Errorf("Load returned %d files, want none", n)
+				}
+				return
+			}
+			if _, err := files.FindFileByPath(tt.wantFile); err != nil {
+				t.Errorf("Load result: %v", err)
+			}
+			for _, name := range []string{"Outer", "Outer.Inner"} {
+				mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(tt.pkg + "." + name))
+				if err != nil {
+					t.Errorf("message %v not registered: %v", name, err)
+					continue
+				}
+				if m, ok := mt.New().Interface().(*dynamicpb.Message); !ok {
+					t.Errorf("message %v registered as %T, want *dynamicpb.Message", name, m)
+				}
+			}
+			if _, err := protoregistry.GlobalTypes.FindExtensionByName(protoreflect.FullName(tt.pkg + ".ext")); err != nil {
+				t.Errorf("extension not registered: %v", err)
+			}
+			// Types linked into the binary are not replaced.
+			mt, err := protoregistry.GlobalTypes.FindMessageByName("google.protobuf.Duration")
+			if err != nil {
+				t.Fatalf("google.protobuf.Duration not registered: %v", err)
+			}
+			if _, ok := mt.New().Interface().(*durationpb.Duration); !ok {
+				t.Errorf("google.protobuf.Duration registered as %T, want *durationpb.Duration", mt.New().Interface())
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.pb")); err == nil {
+		t.Errorf("Load of missing file succeeded, want error")
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	durationFile := protodesc.ToFileDescriptorProto(durationpb.File_google_protobuf_duration_proto)
+	if _, err := Load(writeFile(t, marshalSet(t, durationFile, testFile("descset.newmessage")))); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		wantType string
+		wantErr  bool
+	}{
+		{name: "descset.newmessage.Outer", wantType: "*dynamicpb.Message"},
+		{name: "descset.newmessage.Outer.Inner", wantType: "*dynamicpb.Message"},
+		{name: "google.protobuf.Duration", wantType: "*durationpb.Duration"},
+		{name: "descset.newmessage.Missing", wantErr: true},
+		{name: "descset.newmessage.ext", wantErr: true},
+	}
+	for _, tt := range tests {
+		m, err := NewMessage(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewMessage(%q) succeeded, want error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewMessage(%q) error: %v", tt.name, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", proto.MessageV2(m)); got != tt.wantType {
+			t.Errorf("NewMessage(%q) = %v, want %v", tt.name, got, tt.wantType)
+		}
+	}
+}
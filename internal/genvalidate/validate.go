@@ -0,0 +1,318 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package genvalidate contains the generator of Validate methods, which check
+// the rules of the "github.com/golang/protobuf/validate" package.
+package genvalidate
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/golang/protobuf/validate/rules"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	fmtPackage      = protogen.GoImportPath("fmt")
+	regexpPackage   = protogen.GoImportPath("regexp")
+	sortPackage     = protogen.GoImportPath("sort")
+	utf8Package     = protogen.GoImportPath("unicode/utf8")
+	validatePackage = protogen.GoImportPath("github.com/golang/protobuf/validate")
+)
+
+// GenerateFileContent generates a Validate method for each message in file,
+// excluding the package statement.
+func GenerateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile) error {
+	for _, message := range file.Messages {
+		if err := genMessage(g, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func genMessage(g *protogen.GeneratedFile, message *protogen.Message) error {
+	for _, m := range message.Messages {
+		if err := genMessage(g, m); err != nil {
+			return err
+		}
+	}
+	if message.Desc.IsMapEntry() {
+		return nil
+	}
+	for _, field := range message.Fields {
+		if field.GoName == "Validate" {
+			// The method would conflict with the field,
+			// so leave validation to protobuf reflection.
+			return nil
+		}
+	}
+
+	var patterns []string // declarations of compiled patterns
+	g.P("// Validate checks x and all messages embedded in it against the validation")
+	g.P("// rules of their fields, except for extension fields.")
+	g.P("// If there are violations, it reports a *", validatePackage.Ident("Error"), " listing all of them.")
+	g.P("func (x *", message.GoIdent, ") Validate() error {")
+	g.P("if x == nil {")
+	g.P("return nil")
+	g.P("}")
+	g.P("var errs ", validatePackage.Ident("Error"))
+	for _, field := range message.Fields {
+		r, _ := proto.GetExtension(field.Desc.Options(), rules.E_Rules).(*rules.FieldRules)
+		if r == nil {
+			if field.Desc.Message() == nil {
+				continue
+			}
+			r = new(rules.FieldRules)
+		}
+		if err := checkRules(field, r); err != nil {
+			return err
+		}
+		if r.Pattern != nil {
+			name := fmt.Sprintf("_%s_%s_pattern", message.GoIdent.GoName, field.GoName)
+			patterns = append(patterns, fmt.Sprintf("var %s = %s(%q)", name, g.QualifiedGoIdent(regexpPackage.Ident("MustCompile")), r.GetPattern()))
+		}
+		genField(g, message, field, r)
+	}
+	g.P("return errs.Err()")
+	g.P("}")
+	g.P()
+	for _, p := range patterns {
+		g.P(p)
+	}
+	if len(patterns) > 0 {
+		g.P()
+	}
+	return nil
+}
+
+// checkRules reports an error for rules that cannot be generated.
+func checkRules(field *protogen.Field, r *rules.FieldRules) error {
+	for _, f := range []*float64{r.Min, r.Max} {
+		if f != nil && (math.IsInf(*f, 0) || math.IsNaN(*f)) {
+			return fmt.Errorf("%v: invalid bound %v", field.Desc.FullName(), *f)
+		}
+	}
+	if r.Pattern != nil {
+		if _, err := regexp.Compile(r.GetPattern()); err != nil {
+			return fmt.Errorf("%v: invalid pattern: %v", field.Desc.FullName(), err)
+		}
+	}
+	return nil
+}
+
+func genField(g *protogen.GeneratedFile, message *protogen.Message, field *protogen.Field, r *rules.FieldRules) {
+	path := strconv.Quote(string(field.Desc.Name()))
+	switch {
+	case field.Desc.IsList() || field.Desc.IsMap():
+		if r.GetRequired() {
+			g.P("if len(x.", field.GoName, ") == 0 {")
+			g.P("errs.Append(", path, `, "required", nil, nil)`)
+			g.P("}")
+		}
+		if r.MinItems != nil {
+			g.P("if n := len(x.", field.GoName, "); uint64(n) < ", r.GetMinItems(), " {")
+			g.P("errs.Append(", path, `, "min_items", n, uint64(`, r.GetMinItems(), "))")
+			g.P("}")
+		}
+		if r.MaxItems != nil {
+			g.P("if n := len(x.", field.GoName, "); uint64(n) > ", r.GetMaxItems(), " {")
+			g.P("errs.Append(", path, `, "max_items", n, uint64(`, r.GetMaxItems(), "))")
+			g.P("}")
+		}
+		if field.Desc.IsList() {
+			if !hasValueRules(field.Desc, r) {
+				return
+			}
+			elemPath := g.QualifiedGoIdent(fmtPackage.Ident("Sprintf")) + "(" + strconv.Quote(string(field.Desc.Name())+"[%d]") + ", i)"
+			g.P("for i, v := range x.", field.GoName, " {")
+			genValue(g, message, field, field.Desc, "v", elemPath, r)
+			g.P("}")
+			return
+		}
+		value := field.Message.Fields[1]
+		if !hasValueRules(value.Desc, r) {
+			return
+		}
+		verb := "%v"
+		if field.Desc.MapKey().Kind() == protoreflect.StringKind {
+			verb = "%q"
+		}
+		elemPath := g.QualifiedGoIdent(fmtPackage.Ident("Sprintf")) + "(" + strconv.Quote(string(field.Desc.Name())+"["+verb+"]") + ", k)"
+		// Visit the entries in order of their keys for a deterministic
+		// order of the violations.
+		less := "keys[i] < keys[j]"
+		if field.Desc.MapKey().Kind() == protoreflect.BoolKind {
+			less = "!keys[i] && keys[j]"
+		}
+		g.P("if len(x.", field.GoName, ") > 0 {")
+		g.P("keys := make([]", goMapKeyType(field.Desc.MapKey()), ", 0, len(x.", field.GoName, "))")
+		g.P("for k := range x.", field.GoName, " {")
+		g.P("keys = append(keys, k)")
+		g.P("}")
+		g.P(sortPackage.Ident("Slice"), "(keys, func(i, j int) bool { return ", less, " })")
+		g.P("for _, k := range keys {")
+		g.P("v := x.", field.GoName, "[k]")
+		genValue(g, message, field, value.Desc, "v", elemPath, r)
+		g.P("}")
+		g.P("}")
+	case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() && !hasValueRules(field.Desc, r):
+		if r.GetRequired() {
+			g.P("if _, ok := x.", field.Oneof.GoName, ".(*", field.GoIdent, "); !ok {")
+			g.P("errs.Append(", path, `, "required", nil, nil)`)
+			g.P("}")
+		}
+	case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic():
+		g.P("if o, ok := x.", field.Oneof.GoName, ".(*", field.GoIdent, "); ok {")
+		genValue(g, message, field, field.Desc, "o."+field.GoName, path, r)
+		if r.GetRequired() {
+			g.P("} else {")
+			g.P("errs.Append(", path, `, "required", nil, nil)`)
+		}
+		g.P("}")
+	case field.Desc.HasPresence() && !hasValueRules(field.Desc, r):
+		if r.GetRequired() {
+			g.P("if x.", field.GoName, " == nil {")
+			g.P("errs.Append(", path, `, "required", nil, nil)`)
+			g.P("}")
+		}
+	case field.Desc.HasPresence():
+		v := "x." + field.GoName
+		if field.Desc.Message() == nil && field.Desc.Kind() != protoreflect.BytesKind {
+			v = "*" + v
+		}
+		g.P("if x.", field.GoName, " != nil {")
+		genValue(g, message, field, field.Desc, v, path, r)
+		if r.GetRequired() {
+			g.P("} else {")
+			g.P("errs.Append(", path, `, "required", nil, nil)`)
+		}
+		g.P("}")
+	default:
+		if r.GetRequired() {
+			var zero string
+			switch field.Desc.Kind() {
+			case protoreflect.BoolKind:
+				zero = "!x." + field.GoName
+			case protoreflect.StringKind:
+				zero = "x." + field.GoName + ` == ""`
+			case protoreflect.BytesKind:
+				zero = "len(x." + field.GoName + ") == 0"
+			default:
+				zero = "x." + field.GoName + " == 0"
+			}
+			g.P("if ", zero, " {")
+			g.P("errs.Append(", path, `, "required", nil, nil)`)
+			g.P("}")
+		}
+		genValue(g, message, field, field.Desc, "x."+field.GoName, path, r)
+	}
+}
+
+// hasValueRules reports whether genValue generates any code for
+// the values of fd.
+func hasValueRules(fd protoreflect.FieldDescriptor, r *rules.FieldRules) bool {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return true
+	case protoreflect.StringKind:
+		return r.MinLen != nil || r.MaxLen != nil || r.Pattern != nil
+	case protoreflect.BytesKind:
+		return r.MinLen != nil || r.MaxLen != nil
+	case protoreflect.EnumKind:
+		return r.GetDefinedOnly()
+	case protoreflect.BoolKind:
+		return false
+	default:
+		return r.Min != nil || r.Max != nil
+	}
+}
+
+// genValue generates the checks of the value v of fd, which is an element
+// of field if it is repeated. The path of v is given by the expression path.
+// goMapKeyType returns the Go type of the map key field fd.
+func goMapKeyType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	default:
+		return "string"
+	}
+}
+
+func genValue(g *protogen.GeneratedFile, message *protogen.Message, field *protogen.Field, fd protoreflect.FieldDescriptor, v, path string, r *rules.FieldRules) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		g.P("errs.AppendNested(", path, ", ", v, ")")
+	case protoreflect.StringKind:
+		genLen(g, g.QualifiedGoIdent(utf8Package.Ident("RuneCountInString"))+"("+v+")", path, r)
+		if r.Pattern != nil {
+			name := fmt.Sprintf("_%s_%s_pattern", message.GoIdent.GoName, field.GoName)
+			g.P("if !", name, ".MatchString(", v, ") {")
+			g.P("errs.Append(", path, `, "pattern", `, v, ", ", strconv.Quote(r.GetPattern()), ")")
+			g.P("}")
+		}
+	case protoreflect.BytesKind:
+		genLen(g, "len("+v+")", path, r)
+	case protoreflect.EnumKind:
+		if !r.GetDefinedOnly() {
+			return
+		}
+		var enum *protogen.Enum
+		if field.Desc.IsMap() {
+			enum = field.Message.Fields[1].Enum
+		} else {
+			enum = field.Enum
+		}
+		g.P("if _, ok := ", enum.GoIdent, "_name[int32(", v, ")]; !ok {")
+		g.P("errs.Append(", path, `, "defined_only", int32(`, v, "), nil)")
+		g.P("}")
+	case protoreflect.BoolKind:
+	default:
+		if r.Min != nil {
+			bound := strconv.FormatFloat(r.GetMin(), 'g', -1, 64)
+			g.P("if float64(", v, ") < ", bound, " {")
+			g.P("errs.Append(", path, `, "min", `, v, ", float64(", bound, "))")
+			g.P("}")
+		}
+		if r.Max != nil {
+			bound := strconv.FormatFloat(r.GetMax(), 'g', -1, 64)
+			g.P("if float64(", v, ") > ", bound, " {")
+			g.P("errs.Append(", path, `, "max", `, v, ", float64(", bound, "))")
+			g.P("}")
+		}
+	}
+}
+
+func genLen(g *protogen.GeneratedFile, n, path string, r *rules.FieldRules) {
+	switch {
+	case r.MinLen != nil && r.MaxLen != nil:
+		g.P("if n := ", n, "; uint64(n) < ", r.GetMinLen(), " {")
+		g.P("errs.Append(", path, `, "min_len", n, uint64(`, r.GetMinLen(), "))")
+		g.P("} else if uint64(n) > ", r.GetMaxLen(), " {")
+		g.P("errs.Append(", path, `, "max_len", n, uint64(`, r.GetMaxLen(), "))")
+		g.P("}")
+	case r.MinLen != nil:
+		g.P("if n := ", n, "; uint64(n) < ", r.GetMinLen(), " {")
+		g.P("errs.Append(", path, `, "min_len", n, uint64(`, r.GetMinLen(), "))")
+		g.P("}")
+	case r.MaxLen != nil:
+		g.P("if n := ", n, "; uint64(n) > ", r.GetMaxLen(), " {")
+		g.P("errs.Append(", path, `, "max_len", n, uint64(`, r.GetMaxLen(), "))")
+		g.P("}")
+	}
+}
@@ -12,6 +12,12 @@
 #	protoc:        v3.9.1
 #	protoc-gen-go: v1.3.2
 
-for X in $(find . -name "*.proto" | sed "s|^\./||"); do
+for X in $(find . -name "*.proto" -not -path "./validate_proto/*" | sed "s|^\./||"); do
 	protoc -I$(pwd) --go_out=paths=source_relative:. $X
 done
+
+# The validate_proto test protos exercise the validate plugin of the
+# protoc-gen-go in this repository, so they are generated with it.
+go build -o /tmp/protoc-gen-go-validate ../../protoc-gen-go
+protoc -I$(pwd) -I$(git rev-parse --show-toplevel) --plugin=protoc-gen-go=/tmp/protoc-gen-go-validate \
+	--go_out=plugins=validate,paths=source_relative:. validate_proto/test.proto
@@ -0,0 +1,742 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: validate_proto/test.proto
+
+package validate_proto
+
+import (
+	fmt "fmt"
+	validate "github.com/golang/protobuf/validate"
+	_ "github.com/golang/protobuf/validate/rules"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	regexp "regexp"
+	sort "sort"
+	sync "sync"
+	utf8 "unicode/utf8"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Color int32
+
+const (
+	Color_COLOR_UNSPECIFIED Color = 0
+	Color_RED               Color = 1
+	Color_GREEN             Color = 2
+)
+
+// Enum value maps for Color.
+var (
+	Color_name = map[int32]string{
+		0: "COLOR_UNSPECIFIED",
+		1: "RED",
+		2: "GREEN",
+	}
+	Color_value = map[string]int32{
+		"COLOR_UNSPECIFIED": 0,
+		"RED":               1,
+		"GREEN":             2,
+	}
+)
+
+func (x Color) Enum() *Color {
+	p := new(Color)
+	*p = x
+	return p
+}
+
+func (x Color) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Color) Descriptor() protoreflect.EnumDescriptor {
+	return file_validate_proto_test_proto_enumTypes[0].Descriptor()
+}
+
+func (Color) Type() protoreflect.EnumType {
+	return &file_validate_proto_test_proto_enumTypes[0]
+}
+
+func (x Color) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Color.Descriptor instead.
+func (Color) EnumDescriptor() ([]byte, []int) {
+	return file_validate_proto_test_proto_rawDescGZIP(), []int{0}
+}
+
+type Person struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Age     int32               `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty"`
+	Email   string              `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Color   Color               `protobuf:"varint,4,opt,name=color,proto3,enum=validate_test.Color" json:"color,omitempty"`
+	Tags    []string            `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Address *Address            `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	Friends []*Person           `protobuf:"bytes,7,rep,name=friends,proto3" json:"friends,omitempty"`
+	Homes   map[string]*Address `protobuf:"bytes,8,rep,name=homes,proto3" json:"homes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Types that are assignable to Contact:
+	//	*Person_Phone
+	//	*Person_Office
+	Contact isPerson_Contact `protobuf_oneof:"contact"`
+	Score   *float64         `protobuf:"fixed64,11,opt,name=score,proto3,oneof" json:"score,omitempty"`
+	Avatar  []byte           `protobuf:"bytes,12,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Palette []Color          `protobuf:"varint,13,rep,packed,name=palette,proto3,enum=validate_test.Color" json:"palette,omitempty"`
+	Counts  map[int32]uint64 `protobuf:"bytes,14,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *Person) Reset() {
+	*x = Person{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validate_proto_test_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Person) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Person) ProtoMessage() {}
+
+func (x *Person) ProtoReflect() protoreflect.Message {
+	mi := &file_validate_proto_test_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Person.ProtoReflect.Descriptor instead.
+func (*Person) Descriptor() ([]byte, []int) {
+	return file_validate_proto_test_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Person) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Person) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *Person) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Person) GetColor() Color {
+	if x != nil {
+		return x.Color
+	}
+	return Color_COLOR_UNSPECIFIED
+}
+
+func (x *Person) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Person) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Person) GetFriends() []*Person {
+	if x != nil {
+		return x.Friends
+	}
+	return nil
+}
+
+func (x *Person) GetHomes() map[string]*Address {
+	if x != nil {
+		return x.Homes
+	}
+	return nil
+}
+
+func (m *Person) GetContact() isPerson_Contact {
+	if m != nil {
+		return m.Contact
+	}
+	return nil
+}
+
+func (x *Person) GetPhone() string {
+	if x, ok := x.GetContact().(*Person_Phone); ok {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Person) GetOffice() *Address {
+	if x, ok := x.GetContact().(*Person_Office); ok {
+		return x.Office
+	}
+	return nil
+}
+
+func (x *Person) GetScore() float64 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *Person) GetAvatar() []byte {
+	if x != nil {
+		return x.Avatar
+	}
+	return nil
+}
+
+func (x *Person) GetPalette() []Color {
+	if x != nil {
+		return x.Palette
+	}
+	return nil
+}
+
+func (x *Person) GetCounts() map[int32]uint64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type isPerson_Contact interface {
+	isPerson_Contact()
+}
+
+type Person_Phone struct {
+	Phone string `protobuf:"bytes,9,opt,name=phone,proto3,oneof"`
+}
+
+type Person_Office struct {
+	Office *Address `protobuf:"bytes,10,opt,name=office,proto3,oneof"`
+}
+
+func (*Person_Phone) isPerson_Contact() {}
+
+func (*Person_Office) isPerson_Contact() {}
+
+type Address struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	City string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	Zip  uint64 `protobuf:"varint,2,opt,name=zip,proto3" json:"zip,omitempty"`
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validate_proto_test_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_validate_proto_test_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_validate_proto_test_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetZip() uint64 {
+	if x != nil {
+		return x.Zip
+	}
+	return 0
+}
+
+type Legacy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    *string  `protobuf:"bytes,1,opt,name=id,proto3,oneof" json:"id,omitempty"`
+	Color *Color   `protobuf:"varint,2,opt,name=color,proto3,enum=validate_test.Color,oneof" json:"color,omitempty"`
+	Delta *int64   `protobuf:"zigzag64,3,opt,name=delta,proto3,oneof" json:"delta,omitempty"`
+	Ids   []uint32 `protobuf:"fixed32,4,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Blob  []byte   `protobuf:"bytes,5,opt,name=blob,proto3,oneof" json:"blob,omitempty"`
+}
+
+func (x *Legacy) Reset() {
+	*x = Legacy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validate_proto_test_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Legacy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Legacy) ProtoMessage() {}
+
+func (x *Legacy) ProtoReflect() protoreflect.Message {
+	mi := &file_validate_proto_test_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Legacy.ProtoReflect.Descriptor instead.
+func (*Legacy) Descriptor() ([]byte, []int) {
+	return file_validate_proto_test_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Legacy) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *Legacy) GetColor() Color {
+	if x != nil && x.Color != nil {
+		return *x.Color
+	}
+	return Color_COLOR_UNSPECIFIED
+}
+
+func (x *Legacy) GetDelta() int64 {
+	if x != nil && x.Delta != nil {
+		return *x.Delta
+	}
+	return 0
+}
+
+func (x *Legacy) GetIds() []uint32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *Legacy) GetBlob() []byte {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+var File_validate_proto_test_proto protoreflect.FileDescriptor
+
+var file_validate_proto_test_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x2f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x2f, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc1, 0x06, 0x0a, 0x06, 0x50, 0x65, 0x72, 0x73, 0x6f,
+	0x6e, 0x12, 0x1c, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x08, 0x9a, 0xd5, 0x18, 0x04, 0x08, 0x01, 0x28, 0x0a, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x28, 0x0a, 0x03, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x16, 0x9a, 0xd5,
+	0x18, 0x12, 0x11, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x19, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0xc0, 0x62, 0x40, 0x52, 0x03, 0x61, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x65, 0x6d, 0x61,
+	0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x13, 0x9a, 0xd5, 0x18, 0x0f, 0x32, 0x0d,
+	0x5e, 0x5b, 0x5e, 0x40, 0x5d, 0x2b, 0x40, 0x5b, 0x5e, 0x40, 0x5d, 0x2b, 0x24, 0x52, 0x05, 0x65,
+	0x6d, 0x61, 0x69, 0x6c, 0x12, 0x32, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74,
+	0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x42, 0x06, 0x9a, 0xd5, 0x18, 0x02, 0x38,
+	0x01, 0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x42, 0x08, 0x9a, 0xd5, 0x18, 0x04, 0x20, 0x01, 0x48, 0x03,
+	0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x38, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42,
+	0x06, 0x9a, 0xd5, 0x18, 0x02, 0x08, 0x01, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x2f, 0x0a, 0x07, 0x66, 0x72, 0x69, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x07, 0x66, 0x72, 0x69, 0x65, 0x6e, 0x64,
+	0x73, 0x12, 0x3e, 0x0a, 0x05, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74,
+	0x2e, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x2e, 0x48, 0x6f, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x42, 0x06, 0x9a, 0xd5, 0x18, 0x02, 0x48, 0x02, 0x52, 0x05, 0x68, 0x6f, 0x6d, 0x65,
+	0x73, 0x12, 0x1e, 0x0a, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x06, 0x9a, 0xd5, 0x18, 0x02, 0x20, 0x05, 0x48, 0x00, 0x52, 0x05, 0x70, 0x68, 0x6f, 0x6e,
+	0x65, 0x12, 0x30, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x69, 0x63, 0x65, 0x12, 0x31, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x01, 0x42, 0x16, 0x9a, 0xd5, 0x18, 0x12, 0x11, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, 0x48, 0x01, 0x52, 0x05, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x06, 0x9a, 0xd5, 0x18, 0x02, 0x28, 0x04, 0x52, 0x06,
+	0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x36, 0x0a, 0x07, 0x70, 0x61, 0x6c, 0x65, 0x74, 0x74,
+	0x65, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x42, 0x06, 0x9a,
+	0xd5, 0x18, 0x02, 0x38, 0x01, 0x52, 0x07, 0x70, 0x61, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x12, 0x48,
+	0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x42, 0x0d, 0x9a, 0xd5, 0x18, 0x09, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x24, 0x40,
+	0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x1a, 0x50, 0x0a, 0x0a, 0x48, 0x6f, 0x6d, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x46, 0x0a, 0x07, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1a, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x06, 0x9a, 0xd5, 0x18, 0x02, 0x08, 0x01, 0x52, 0x04, 0x63, 0x69, 0x74,
+	0x79, 0x12, 0x1f, 0x0a, 0x03, 0x7a, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42, 0x0d,
+	0x9a, 0xd5, 0x18, 0x09, 0x19, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x69, 0xf8, 0x40, 0x52, 0x03, 0x7a,
+	0x69, 0x70, 0x22, 0xf2, 0x01, 0x0a, 0x06, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x12, 0x1d, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x9a, 0xd5, 0x18, 0x04, 0x08,
+	0x01, 0x20, 0x02, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x05,
+	0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6c, 0x6f,
+	0x72, 0x42, 0x06, 0x9a, 0xd5, 0x18, 0x02, 0x38, 0x01, 0x48, 0x01, 0x52, 0x05, 0x63, 0x6f, 0x6c,
+	0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x12, 0x42, 0x0d, 0x9a, 0xd5, 0x18, 0x09, 0x11, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x14, 0xc0, 0x48, 0x02, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x88, 0x01, 0x01, 0x12,
+	0x21, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x07, 0x42, 0x0f, 0x9a, 0xd5,
+	0x18, 0x0b, 0x08, 0x01, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x59, 0x40, 0x52, 0x03, 0x69,
+	0x64, 0x73, 0x12, 0x1f, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x42, 0x06, 0x9a, 0xd5, 0x18, 0x02, 0x08, 0x01, 0x48, 0x03, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x62,
+	0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x63,
+	0x6f, 0x6c, 0x6f, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x42, 0x07,
+	0x0a, 0x05, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x2a, 0x32, 0x0a, 0x05, 0x43, 0x6f, 0x6c, 0x6f, 0x72,
+	0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f, 0x4c, 0x4f, 0x52, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x45, 0x44, 0x10, 0x01,
+	0x12, 0x09, 0x0a, 0x05, 0x47, 0x52, 0x45, 0x45, 0x4e, 0x10, 0x02, 0x42, 0x3f, 0x5a, 0x3d, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2f, 0x74, 0x65, 0x73, 0x74, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_validate_proto_test_proto_rawDescOnce sync.Once
+	file_validate_proto_test_proto_rawDescData = file_validate_proto_test_proto_rawDesc
+)
+
+func file_validate_proto_test_proto_rawDescGZIP() []byte {
+	file_validate_proto_test_proto_rawDescOnce.Do(func() {
+		file_validate_proto_test_proto_rawDescData = protoimpl.X.CompressGZIP(file_validate_proto_test_proto_rawDescData)
+	})
+	return file_validate_proto_test_proto_rawDescData
+}
+
+var file_validate_proto_test_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_validate_proto_test_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_validate_proto_test_proto_goTypes = []interface{}{
+	(Color)(0),      // 0: validate_test.Color
+	(*Person)(nil),  // 1: validate_test.Person
+	(*Address)(nil), // 2: validate_test.Address
+	(*Legacy)(nil),  // 3: validate_test.Legacy
+	nil,             // 4: validate_test.Person.HomesEntry
+	nil,             // 5: validate_test.Person.CountsEntry
+}
+var file_validate_proto_test_proto_depIdxs = []int32{
+	0, // 0: validate_test.Person.color:type_name -> validate_test.Color
+	2, // 1: validate_test.Person.address:type_name -> validate_test.Address
+	1, // 2: validate_test.Person.friends:type_name -> validate_test.Person
+	4, // 3: validate_test.Person.homes:type_name -> validate_test.Person.HomesEntry
+	2, // 4: validate_test.Person.office:type_name -> validate_test.Address
+	0, // 5: validate_test.Person.palette:type_name -> validate_test.Color
+	5, // 6: validate_test.Person.counts:type_name -> validate_test.Person.CountsEntry
+	0, // 7: validate_test.Legacy.color:type_name -> validate_test.Color
+	2, // 8: validate_test.Person.HomesEntry.value:type_name -> validate_test.Address
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_validate_proto_test_proto_init() }
+func file_validate_proto_test_proto_init() {
+	if File_validate_proto_test_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_validate_proto_test_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Person); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_validate_proto_test_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Address); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_validate_proto_test_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Legacy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_validate_proto_test_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Person_Phone)(nil),
+		(*Person_Office)(nil),
+	}
+	file_validate_proto_test_proto_msgTypes[2].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_validate_proto_test_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_validate_proto_test_proto_goTypes,
+		DependencyIndexes: file_validate_proto_test_proto_depIdxs,
+		EnumInfos:         file_validate_proto_test_proto_enumTypes,
+		MessageInfos:      file_validate_proto_test_proto_msgTypes,
+	}.Build()
+	File_validate_proto_test_proto = out.File
+	file_validate_proto_test_proto_rawDesc = nil
+	file_validate_proto_test_proto_goTypes = nil
+	file_validate_proto_test_proto_depIdxs = nil
+}
+
+// Validate checks x and all messages embedded in it against the validation
+// rules of their fields, except for extension fields.
+// If there are violations, it reports a *validate.Error listing all of them.
+func (x *Person) Validate() error {
+	if x == nil {
+		return nil
+	}
+	var errs validate.Error
+	if x.Name == "" {
+		errs.Append("name", "required", nil, nil)
+	}
+	if n := utf8.RuneCountInString(x.Name); uint64(n) > 10 {
+		errs.Append("name", "max_len", n, uint64(10))
+	}
+	if float64(x.Age) < 0 {
+		errs.Append("age", "min", x.Age, float64(0))
+	}
+	if float64(x.Age) > 150 {
+		errs.Append("age", "max", x.Age, float64(150))
+	}
+	if !_Person_Email_pattern.MatchString(x.Email) {
+		errs.Append("email", "pattern", x.Email, "^[^@]+@[^@]+$")
+	}
+	if _, ok := Color_name[int32(x.Color)]; !ok {
+		errs.Append("color", "defined_only", int32(x.Color), nil)
+	}
+	if n := len(x.Tags); uint64(n) > 3 {
+		errs.Append("tags", "max_items", n, uint64(3))
+	}
+	for i, v := range x.Tags {
+		if n := utf8.RuneCountInString(v); uint64(n) < 1 {
+			errs.Append(fmt.Sprintf("tags[%d]", i), "min_len", n, uint64(1))
+		}
+	}
+	if x.Address != nil {
+		errs.AppendNested("address", x.Address)
+	} else {
+		errs.Append("address", "required", nil, nil)
+	}
+	for i, v := range x.Friends {
+		errs.AppendNested(fmt.Sprintf("friends[%d]", i), v)
+	}
+	if n := len(x.Homes); uint64(n) > 2 {
+		errs.Append("homes", "max_items", n, uint64(2))
+	}
+	if len(x.Homes) > 0 {
+		keys := make([]string, 0, len(x.Homes))
+		for k := range x.Homes {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		for _, k := range keys {
+			v := x.Homes[k]
+			errs.AppendNested(fmt.Sprintf("homes[%q]", k), v)
+		}
+	}
+	if o, ok := x.Contact.(*Person_Phone); ok {
+		if n := utf8.RuneCountInString(o.Phone); uint64(n) < 5 {
+			errs.Append("phone", "min_len", n, uint64(5))
+		}
+	}
+	if o, ok := x.Contact.(*Person_Office); ok {
+		errs.AppendNested("office", o.Office)
+	}
+	if x.Score != nil {
+		if float64(*x.Score) < 0 {
+			errs.Append("score", "min", *x.Score, float64(0))
+		}
+		if float64(*x.Score) > 1 {
+			errs.Append("score", "max", *x.Score, float64(1))
+		}
+	}
+	if n := len(x.Avatar); uint64(n) > 4 {
+		errs.Append("avatar", "max_len", n, uint64(4))
+	}
+	for i, v := range x.Palette {
+		if _, ok := Color_name[int32(v)]; !ok {
+			errs.Append(fmt.Sprintf("palette[%d]", i), "defined_only", int32(v), nil)
+		}
+	}
+	if len(x.Counts) > 0 {
+		keys := make([]int32, 0, len(x.Counts))
+		for k := range x.Counts {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		for _, k := range keys {
+			v := x.Counts[k]
+			if float64(v) > 10 {
+				errs.Append(fmt.Sprintf("counts[%v]", k), "max", v, float64(10))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+var _Person_Email_pattern = regexp.MustCompile("^[^@]+@[^@]+$")
+
+// Validate checks x and all messages embedded in it against the validation
+// rules of their fields, except for extension fields.
+// If there are violations, it reports a *validate.Error listing all of them.
+func (x *Address) Validate() error {
+	if x == nil {
+		return nil
+	}
+	var errs validate.Error
+	if x.City == "" {
+		errs.Append("city", "required", nil, nil)
+	}
+	if float64(x.Zip) > 99999 {
+		errs.Append("zip", "max", x.Zip, float64(99999))
+	}
+	return errs.Err()
+}
+
+// Validate checks x and all messages embedded in it against the validation
+// rules of their fields, except for extension fields.
+// If there are violations, it reports a *validate.Error listing all of them.
+func (x *Legacy) Validate() error {
+	if x == nil {
+		return nil
+	}
+	var errs validate.Error
+	if x.Id != nil {
+		if n := utf8.RuneCountInString(*x.Id); uint64(n) < 2 {
+			errs.Append("id", "min_len", n, uint64(2))
+		}
+	} else {
+		errs.Append("id", "required", nil, nil)
+	}
+	if x.Color != nil {
+		if _, ok := Color_name[int32(*x.Color)]; !ok {
+			errs.Append("color", "defined_only", int32(*x.Color), nil)
+		}
+	}
+	if x.Delta != nil {
+		if float64(*x.Delta) < -5 {
+			errs.Append("delta", "min", *x.Delta, float64(-5))
+		}
+	}
+	if len(x.Ids) == 0 {
+		errs.Append("ids", "required", nil, nil)
+	}
+	for i, v := range x.Ids {
+		if float64(v) > 100 {
+			errs.Append(fmt.Sprintf("ids[%d]", i), "max", v, float64(100))
+		}
+	}
+	if x.Blob == nil {
+		errs.Append("blob", "required", nil, nil)
+	}
+	return errs.Err()
+}
@@ -5,6 +5,7 @@
 package jsonpb
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -48,6 +49,15 @@ type Marshaler struct {
 	// AnyResolver is used to resolve the google.protobuf.Any well-known type.
 	// If unset, the global registry is used by default.
 	AnyResolver AnyResolver
+
+	// RedactField, if non-nil, reports whether the value of a field is
+	// sensitive and must not be printed. The values of such fields are
+	// replaced with the JSON string "[REDACTED]". Use proto.IsDebugRedact
+	// to redact fields marked with the debug_redact field option.
+	//
+	// Redacted output is intended for logging and debugging;
+	// it cannot be parsed by Unmarshaler.
+	RedactField func(protoreflect.FieldDescriptor) bool
 }
 
 // JSONPBMarshaler is implemented by protobuf messages that customize the
@@ -415,6 +425,10 @@ func (w *jsonWriter) marshalField(fd protoreflect.FieldDescriptor, v protoreflec
 	if w.Indent != "" {
 		w.write(" ")
 	}
+	if w.RedactField != nil && w.RedactField(fd) {
+		w.write(`"[REDACTED]"`)
+		return nil
+	}
 	return w.marshalValue(fd, v, indent)
 }
 
@@ -548,6 +562,10 @@ func (w *jsonWriter) marshalSingularValue(fd protoreflect.FieldDescriptor, v pro
 		case int64, uint64:
 			w.write(fmt.Sprintf(`"%d"`, v.Interface()))
 			return nil
+		case []byte:
+			// Unlike encoding/json, encode unset bytes as an empty string.
+			w.write(`"` + base64.StdEncoding.EncodeToString(v.Bytes()) + `"`)
+			return nil
 		}
 
 		b, err := json.Marshal(v.Interface())
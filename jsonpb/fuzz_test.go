@@ -0,0 +1,74 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package jsonpb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protorand"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/jsonpb_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+// fuzzMessages returns new messages of the types that FuzzUnmarshal
+// decodes each input as.
+func fuzzMessages() []proto.Message {
+	return []proto.Message{
+		new(pb2.Simple),
+		new(pb2.NonFinites),
+		new(pb2.Repeats),
+		new(pb2.Widget),
+		new(pb2.Maps),
+		new(pb2.MsgWithOneof),
+		new(pb2.Real),
+		new(pb2.Complex),
+		new(pb2.KnownTypes),
+		new(pb3.Message),
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, tt := range marshalingTests {
+		f.Add(tt.json)
+	}
+	for _, tt := range unmarshalingTests {
+		f.Add(tt.json)
+	}
+	for _, tt := range unmarshalingShouldError {
+		f.Add(tt.in)
+	}
+	g := protorand.New(1)
+	for _, m := range fuzzMessages() {
+		g.Fill(m)
+		if s, err := marshaler.MarshalToString(m); err == nil {
+			f.Add(s)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, m1 := range fuzzMessages() {
+			if err := Unmarshal(strings.NewReader(s), m1); err != nil {
+				continue
+			}
+			s1, err := marshaler.MarshalToString(m1)
+			if err != nil {
+				t.Fatalf("Marshal(%T) error: %v", m1, err)
+			}
+			m2 := proto.MessageV1(proto.MessageReflect(m1).New().Interface())
+			if err := UnmarshalString(s1, m2); err != nil {
+				t.Fatalf("UnmarshalString(%q) error: %v", s1, err)
+			}
+			if !proto.Equal(m1, m2) {
+				t.Errorf("UnmarshalString(%q) != Unmarshal(input):\ngot:  %v\nwant: %v", s1, m2, m1)
+			}
+		}
+	})
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	pb2 "github.com/golang/protobuf/internal/testprotos/jsonpb_proto"
 	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
@@ -503,6 +504,7 @@ var marshalingTests = []struct {
 	{"BoolValue", marshaler, &pb2.KnownTypes{Bool: &wpb.BoolValue{Value: true}}, `{"bool":true}`},
 	{"StringValue", marshaler, &pb2.KnownTypes{Str: &wpb.StringValue{Value: "plush"}}, `{"str":"plush"}`},
 	{"BytesValue", marshaler, &pb2.KnownTypes{Bytes: &wpb.BytesValue{Value: []byte("wow")}}, `{"bytes":"d293"}`},
+	{"empty BytesValue", marshaler, &pb2.KnownTypes{Bytes: &wpb.BytesValue{}}, `{"bytes":""}`},
 
 	{"required", marshaler, &pb2.MsgWithRequired{Str: proto.String("hello")}, `{"str":"hello"}`},
 	{"required bytes", marshaler, &pb2.MsgWithRequiredBytes{Byts: []byte{}}, `{"byts":""}`},
@@ -598,6 +600,35 @@ func TestMarshalWithCustomValidation(t *testing.T) {
 	}
 }
 
+func TestMarshalRedact(t *testing.T) {
+	m := &pb2.Widget{
+		Color:   pb2.Widget_RED.Enum(),
+		Simple:  &pb2.Simple{OString: proto.String("secret"), OInt32: proto.Int32(7)},
+		RSimple: []*pb2.Simple{{OString: proto.String("hidden")}},
+	}
+	redact := func(fd protoreflect.FieldDescriptor) bool {
+		return fd.Name() == "o_string" || fd.Name() == "color"
+	}
+	const want = `{"color":"[REDACTED]","simple":{"oInt32":7,"oString":"[REDACTED]"},"rSimple":[{"oString":"[REDACTED]"}]}`
+	got, err := (&Marshaler{RedactField: redact}).MarshalToString(m)
+	if err != nil {
+		t.Fatalf("MarshalToString error: %v", err)
+	}
+	if got != want {
+		t.Errorf("MarshalToString with RedactField:\ngot:  %s\nwant: %s", got, want)
+	}
+
+	// Descriptors without the debug_redact option are not redacted.
+	const wantPlain = `{"color":"RED","simple":{"oInt32":7,"oString":"secret"},"rSimple":[{"oString":"hidden"}]}`
+	got, err = (&Marshaler{RedactField: proto.IsDebugRedact}).MarshalToString(m)
+	if err != nil {
+		t.Fatalf("MarshalToString error: %v", err)
+	}
+	if got != wantPlain {
+		t.Errorf("MarshalToString with proto.IsDebugRedact:\ngot:  %s\nwant: %s", got, wantPlain)
+	}
+}
+
 // Test marshaling message containing unset required fields should produce error.
 func TestMarshalUnsetRequiredFields(t *testing.T) {
 	msgExt := &pb2.Real{}
@@ -0,0 +1,284 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/runtime/protoiface"
+)
+
+// SetAliasing specifies whether Unmarshal, DecodeMessage, and DecodeGroup
+// may alias the buffer rather than copy out of it.
+//
+// When aliasing is enabled, the values of bytes fields in the decoded
+// message are sub-slices of the buffer. The caller must then ensure that
+// the buffer contents are not modified for as long as the decoded message
+// is in use. In particular, the buffer must not be reused for further
+// encoding (e.g., with Reset and Marshal), since that overwrites the
+// memory that the message refers to. The aliased slices are capped at
+// their length so that appending to them never writes into the buffer.
+//
+// Only the bytes fields of generated messages are aliased; the values of
+// map entries, extensions, and members of oneofs are copied as usual,
+// as are all fields of messages of other types, such as dynamic messages.
+// All other fields are decoded by Unmarshal, so aliasing is intended for
+// messages whose size is dominated by bytes fields.
+func (b *Buffer) SetAliasing(alias bool) {
+	b.alias = alias
+}
+
+// SetAliasStrings specifies whether, when aliasing is enabled by SetAliasing,
+// the values of string fields also alias the buffer.
+//
+// Go strings are immutable, so modifying the buffer while the decoded
+// message is in use changes the value of supposedly immutable strings,
+// resulting in undefined behavior. Only use this if the buffer is never
+// modified after decoding.
+//
+// Strings are always copied on platforms that disallow package unsafe.
+func (b *Buffer) SetAliasStrings(alias bool) {
+	b.aliasStrings = alias
+}
+
+func (b *Buffer) unmarshalMerge(buf []byte, m Message) error {
+	if !b.alias {
+		return UnmarshalMerge(buf, m)
+	}
+	if _, ok := m.(Unmarshaler); ok {
+		return UnmarshalMerge(buf, m)
+	}
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return UnmarshalMerge(buf, m)
+	}
+	d := &aliasDecoder{aliasStrings: b.aliasStrings, initialized: true}
+	if err := d.unmarshalMessage(buf, rv, 0); err != nil {
+		return err
+	}
+	if d.initialized {
+		return nil
+	}
+	return checkRequiredNotSet(MessageV2(m))
+}
+
+// maxAliasDepth is the maximum nesting depth of messages decoded
+// with aliasing, which matches the default limit of Unmarshal.
+const maxAliasDepth = 10000
+
+var errAliasDepth = errors.New("proto: exceeded maximum recursion depth")
+
+// aliasDecoder decodes the wire format into generated messages, such that
+// the values of bytes fields are sub-slices of the input.
+//
+// It only handles the fields of a message type listed by aliasFieldsOf,
+// and passes each run of other fields to Unmarshal, which validates them
+// and stores their values. Since the fields are decoded in order,
+// the result is the same as that of Unmarshal.
+type aliasDecoder struct {
+	aliasStrings bool
+
+	// initialized reports whether Unmarshal reported all messages as
+	// initialized, as UnmarshalMerge uses to skip checking required fields.
+	initialized bool
+}
+
+// unmarshalMessage merges the wire-format message in b into the generated
+// message pointed to by rv, at the given nesting depth.
+func (d *aliasDecoder) unmarshalMessage(b []byte, rv reflect.Value, depth int) error {
+	if depth > maxAliasDepth {
+		return errAliasDepth
+	}
+	fields := aliasFieldsOf(rv.Type().Elem())
+	var start int // start of the fields left to Unmarshal
+	for i := 0; i < len(b); {
+		num, wtyp, n := protowire.ConsumeTag(b[i:])
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(num, wtyp, b[i+n:])
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		v, end := b[i+n:i+n+m], i+n+m
+
+		f, ok := fields[num]
+		switch {
+		case !ok || f.wireType != wtyp:
+			ok = false
+		case wtyp == protowire.StartGroupType:
+			v = v[:len(v)-protowire.SizeTag(num)] // the end group marker
+		default:
+			v, _ = protowire.ConsumeBytes(v)
+		}
+		if ok && f.kind == reflect.String && (!d.aliasStrings || !utf8.Valid(v)) {
+			// Unmarshal reports invalid UTF-8 if the field requires it.
+			ok = false
+		}
+		if !ok {
+			i = end
+			continue
+		}
+
+		if start < i {
+			if err := d.unmarshalMerge(b[start:i], rv); err != nil {
+				return err
+			}
+		}
+		if err := d.setField(rv.Elem().Field(f.index), f, v[:len(v):len(v)], depth); err != nil {
+			return err
+		}
+		i, start = end, end
+	}
+	// The last call also determines whether the message is initialized
+	// if all of its fields were aliased.
+	return d.unmarshalMerge(b[start:], rv)
+}
+
+// setField stores the value v of the field f in fv.
+func (d *aliasDecoder) setField(fv reflect.Value, f aliasField, v []byte, depth int) error {
+	switch t := fv.Type(); {
+	case f.kind == reflect.Struct && t.Kind() == reflect.Ptr:
+		if fv.IsNil() {
+			fv.Set(reflect.New(t.Elem()))
+		}
+		return d.unmarshalMessage(v, fv, depth+1)
+	case f.kind == reflect.Struct:
+		mv := reflect.New(t.Elem().Elem())
+		if err := d.unmarshalMessage(v, mv, depth+1); err != nil {
+			return err
+		}
+		appendZero(fv).Set(mv)
+	case f.kind == reflect.String && t.Kind() == reflect.Ptr:
+		sv := reflect.New(t.Elem())
+		sv.Elem().SetString(unsafeString(v))
+		fv.Set(sv)
+	case f.kind == reflect.String && t.Kind() == reflect.Slice:
+		appendZero(fv).SetString(unsafeString(v))
+	case f.kind == reflect.String:
+		fv.SetString(unsafeString(v))
+	case t.Elem().Kind() == reflect.Slice:
+		appendZero(fv).SetBytes(v)
+	default:
+		fv.SetBytes(v)
+	}
+	return nil
+}
+
+// appendZero appends a zero element to the slice fv and returns the element.
+// Unlike reflect.Append, it only allocates to grow the slice.
+func appendZero(fv reflect.Value) reflect.Value {
+	n := fv.Len()
+	if n == fv.Cap() {
+		sv := reflect.MakeSlice(fv.Type(), n, 2*n+1)
+		reflect.Copy(sv, fv)
+		fv.Set(sv)
+	}
+	fv.SetLen(n + 1)
+	return fv.Index(n)
+}
+
+// unmarshalMerge merges b into the message pointed to by rv,
+// without checking for unset required fields.
+func (d *aliasDecoder) unmarshalMerge(b []byte, rv reflect.Value) error {
+	out, err := protoV2.UnmarshalOptions{
+		AllowPartial: true,
+		Merge:        true,
+	}.UnmarshalState(protoiface.UnmarshalInput{
+		Buf:     b,
+		Message: MessageV2(rv.Interface()).ProtoReflect(),
+	})
+	if out.Flags&protoiface.UnmarshalInitialized == 0 {
+		d.initialized = false
+	}
+	return err
+}
+
+// aliasField is a field of a generated message that aliasDecoder handles.
+type aliasField struct {
+	index    int            // index of the Go struct field
+	wireType protowire.Type // wire type of the values of the field
+	kind     reflect.Kind   // Slice for bytes, String for strings, or Struct for messages
+}
+
+var aliasFieldsCache sync.Map // map[reflect.Type]map[protowire.Number]aliasField
+
+// aliasFieldsOf returns the fields of the generated message struct t that
+// aliasDecoder handles, by field number: the bytes and string fields that
+// are not members of oneofs, and the message fields whose types have such
+// fields, directly or in their own message fields.
+func aliasFieldsOf(t reflect.Type) map[protowire.Number]aliasField {
+	if fields, ok := aliasFieldsCache.Load(t); ok {
+		return fields.(map[protowire.Number]aliasField)
+	}
+	seen := make(map[reflect.Type]map[protowire.Number]aliasField)
+	fields := computeAliasFields(t, seen)
+	for t, fields := range seen {
+		aliasFieldsCache.LoadOrStore(t, fields)
+	}
+	return fields
+}
+
+// computeAliasFields computes aliasFieldsOf(t), where seen holds the fields
+// of the message types that it has computed or is computing.
+func computeAliasFields(t reflect.Type, seen map[reflect.Type]map[protowire.Number]aliasField) map[protowire.Number]aliasField {
+	if fields, ok := seen[t]; ok {
+		return fields
+	}
+	fields := make(map[protowire.Number]aliasField)
+	seen[t] = fields // a recursive message type handles its own message fields
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := strings.Split(sf.Tag.Get("protobuf"), ",")
+		if len(tag) < 2 {
+			continue // not a field, or a oneof
+		}
+		n, err := strconv.Atoi(tag[1])
+		if err != nil {
+			continue
+		}
+		f := aliasField{index: i, wireType: protowire.BytesType}
+		if tag[0] == "group" {
+			f.wireType = protowire.StartGroupType
+		}
+		switch ft := sf.Type; {
+		case ft == bytesType || ft.Kind() == reflect.Slice && ft.Elem() == bytesType:
+			f.kind = reflect.Slice
+		case ft.Kind() == reflect.String ||
+			ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.String ||
+			ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.String:
+			f.kind = reflect.String
+		default:
+			mt := ft
+			if mt.Kind() == reflect.Slice {
+				mt = mt.Elem()
+			}
+			if mt.Kind() != reflect.Ptr || mt.Elem().Kind() != reflect.Struct || mt.Implements(unmarshalerType) {
+				continue
+			}
+			if len(computeAliasFields(mt.Elem(), seen)) == 0 {
+				continue // Unmarshal decodes the message faster
+			}
+			f.kind = reflect.Struct
+		}
+		if f.kind != reflect.Struct && tag[0] != "bytes" {
+			continue
+		}
+		fields[protowire.Number(n)] = f
+	}
+	return fields
+}
+
+var (
+	bytesType       = reflect.TypeOf([]byte(nil))
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
@@ -0,0 +1,14 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build purego || appengine
+// +build purego appengine
+
+package proto
+
+// unsafeString returns a copy of b as a string,
+// since aliasing is not possible without package unsafe.
+func unsafeString(b []byte) string {
+	return string(b)
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func aliasTestMessages() []proto.Message {
+	packed := initGoTest(false)
+	packed.F_BoolRepeatedPacked = []bool{true, false}
+	packed.F_Int32RepeatedPacked = []int32{-1, 0, 1 << 20}
+	packed.F_Sint64RepeatedPacked = []int64{-5, 5}
+	packed.F_FloatRepeatedPacked = []float32{1.5, -2}
+	packed.F_DoubleRepeatedPacked = []float64{3.25}
+	packed.Repeatedgroup = []*pb2.GoTest_RepeatedGroup{initGoTest_RepeatedGroup(), initGoTest_RepeatedGroup()}
+	packed.Optionalgroup = initGoTest_OptionalGroup()
+
+	return []proto.Message{
+		initGoTest(true),
+		testMsg(),
+		bytesMsg(),
+		packed,
+		messageWithExtension1,
+		&pb2.MyMessage{
+			Count:    proto.Int32(1),
+			Pet:      []string{"horsey", "bunny"},
+			RepBytes: [][]byte{[]byte("a"), nil, []byte("c")},
+			Others:   []*pb2.OtherMessage{{Value: []byte("other")}, {Inner: &pb2.InnerMessage{Host: proto.String("h")}}},
+			Somegroup: &pb2.MyMessage_SomeGroup{
+				GroupField: proto.Int32(9),
+			},
+		},
+		&pb2.MessageWithMap{
+			NameMapping: map[int32]string{1: "one", 2: "two"},
+			MsgMapping:  map[int64]*pb2.FloatingPoint{-1: {F: proto.Float64(1)}},
+			ByteMapping: map[bool][]byte{true: []byte("yes"), false: nil},
+			StrToStr:    map[string]string{"k": "v"},
+		},
+		&pb3.Message{
+			Name:     "Rabbit",
+			Hilarity: pb3.Message_SLAPSTICK,
+			Data:     []byte("data"),
+			Key:      []uint64{1, 2, 3},
+			Nested:   &pb3.Nested{Bunny: "Monty"},
+			Terrain:  map[string]*pb3.Nested{"meadow": {Cute: true}},
+			Children: []*pb3.Message{{Name: "child"}},
+		},
+		&pb3.TestUTF8{
+			Scalar:   "scalar",
+			Vector:   []string{"a", "b"},
+			Oneof:    &pb3.TestUTF8_Field{Field: "field"},
+			MapKey:   map[string]int64{"k": 1},
+			MapValue: map[int64]string{1: "v"},
+		},
+		&pb2.Communique{Union: &pb2.Communique_Data{Data: []byte("oneof bytes")}},
+	}
+}
+
+func TestBufferAliasingUnmarshal(t *testing.T) {
+	for _, m := range aliasTestMessages() {
+		b, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal error: %v", err)
+		}
+		// Append an unknown field to exercise unknown field handling.
+		b = append(b, "\xf8\xff\x07\x01"...)
+		for _, aliasStrings := range []bool{false, true} {
+			want := proto.Clone(m)
+			want.Reset()
+			if err := proto.Unmarshal(b, want); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+
+			got := proto.Clone(m)
+			got.Reset()
+			buf := proto.NewBuffer(b)
+			buf.SetAliasing(true)
+			buf.SetAliasStrings(aliasStrings)
+			if err := buf.Unmarshal(got); err != nil {
+				t.Fatalf("Buffer.Unmarshal with aliasing error: %v", err)
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("Buffer.Unmarshal with aliasing mismatch:\ngot:  %v\nwant: %v", got, want)
+			}
+		}
+	}
+}
+
+func TestBufferAliasing(t *testing.T) {
+	in := &pb3.Message{Name: "name", Data: []byte("data"), Children: []*pb3.Message{{Data: []byte("data")}}}
+	b, err := proto.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	for _, alias := range []bool{false, true} {
+		src := append([]byte(nil), b...)
+		buf := proto.NewBuffer(src)
+		buf.SetAliasing(alias)
+		buf.SetAliasStrings(alias)
+		m := new(pb3.Message)
+		if err := buf.Unmarshal(m); err != nil {
+			t.Fatalf("Buffer.Unmarshal error: %v", err)
+		}
+
+		// Appending to an aliased slice must not overwrite the input.
+		_ = append(m.Data, "!!!!!!!!"...)
+		if !bytes.Equal(src, b) {
+			t.Errorf("appending to decoded bytes (aliasing %v) modified the input buffer", alias)
+		}
+
+		for i := range src {
+			src[i] = 'X'
+		}
+		// Strings are copied on platforms that disallow package unsafe.
+		aliased := string(m.Data) == "XXXX" && string(m.Children[0].Data) == "XXXX" && (m.Name == "XXXX" || m.Name == "name")
+		if aliased != alias || !alias && m.Name != "name" {
+			t.Errorf("with aliasing %v, decoded message is %v after modifying the input buffer", alias, m)
+		}
+	}
+}
+
+func TestBufferAliasingErrors(t *testing.T) {
+	m := &pb3.Message{Name: "name", Nested: &pb3.Nested{Bunny: "bunny"}}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	for i := 1; i < len(b); i++ {
+		wantErr := proto.Unmarshal(b[:i], new(pb3.Message))
+		buf := proto.NewBuffer(b[:i])
+		buf.SetAliasing(true)
+		if err := buf.Unmarshal(new(pb3.Message)); (err == nil) != (wantErr == nil) {
+			t.Errorf("Buffer.Unmarshal of %d truncated bytes error = %v, want %v", i, err, wantErr)
+		}
+	}
+
+	buf := proto.NewBuffer([]byte("\x0a\x01\xff"))
+	buf.SetAliasing(true)
+	if err := buf.Unmarshal(new(pb3.Message)); err == nil {
+		t.Errorf("Buffer.Unmarshal of invalid UTF-8 in proto3 string succeeded, want error")
+	}
+
+	// Invalid UTF-8 is only an error if the syntax requires valid UTF-8.
+	buf = proto.NewBuffer([]byte("\x08\x01\x12\x01\xff"))
+	buf.SetAliasing(true)
+	buf.SetAliasStrings(true)
+	if m := new(pb2.MyMessage); buf.Unmarshal(m) != nil || m.GetName() != "\xff" {
+		t.Errorf("Buffer.Unmarshal of invalid UTF-8 in proto2 string = %v, want name %q", m, "\xff")
+	}
+
+	buf = proto.NewBuffer(nil)
+	buf.SetAliasing(true)
+	if err := buf.Unmarshal(new(pb2.GoTest)); !isRequiredNotSetError(err) {
+		t.Errorf("Buffer.Unmarshal of empty GoTest error = %v, want RequiredNotSetError", err)
+	}
+}
+
+func largeBytesMessage() *pb2.MyMessage {
+	m := &pb2.MyMessage{Count: proto.Int32(1)}
+	for i := 0; i < 100; i++ {
+		m.RepBytes = append(m.RepBytes, bytes.Repeat([]byte{byte(i)}, 1024))
+	}
+	return m
+}
+
+func benchmarkBufferUnmarshal(b *testing.B, alias bool) {
+	data, err := proto.Marshal(largeBytesMessage())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	buf := proto.NewBuffer(nil)
+	buf.SetAliasing(alias)
+	m := new(pb2.MyMessage)
+	for i := 0; i < b.N; i++ {
+		m.Reset()
+		buf.SetBuf(data)
+		if err := buf.Unmarshal(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBufferUnmarshalCopy(b *testing.B)  { benchmarkBufferUnmarshal(b, false) }
+func BenchmarkBufferUnmarshalAlias(b *testing.B) { benchmarkBufferUnmarshal(b, true) }
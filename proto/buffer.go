@@ -7,6 +7,7 @@ package proto
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/encoding/protowire"
@@ -50,6 +51,9 @@ type Buffer struct {
 	buf           []byte
 	idx           int
 	deterministic bool
+	alias         bool
+	aliasStrings  bool
+	parallelism   int
 }
 
 // NewBuffer allocates a new Buffer initialized with buf,
@@ -58,6 +62,38 @@ func NewBuffer(buf []byte) *Buffer {
 	return &Buffer{buf: buf}
 }
 
+// maxPooledBufferSize is the capacity above which a Buffer is not returned
+// to the pool, so that a few large messages do not pin memory indefinitely.
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(Buffer) },
+}
+
+// GetBuffer returns an empty Buffer with default settings from a pool
+// of buffers shared by the process. It is intended for servers that
+// marshal many messages, to reuse the memory of buffers across requests.
+//
+// When done with the Buffer, call PutBuffer to return it to the pool.
+func GetBuffer() *Buffer {
+	return bufferPool.Get().(*Buffer)
+}
+
+// PutBuffer resets b and returns it to the pool used by GetBuffer.
+//
+// The internal buffer of b is reused by later calls to GetBuffer.
+// Thus, neither b nor any slice obtained from it (e.g., by Bytes or Unread)
+// may be used after the call. Copy any data that must be retained,
+// and do not pass a Buffer whose internal buffer was provided by the
+// caller (e.g., by NewBuffer or SetBuf) and is referenced elsewhere.
+func PutBuffer(b *Buffer) {
+	if cap(b.buf) > maxPooledBufferSize {
+		return
+	}
+	*b = Buffer{buf: b.buf[:0]}
+	bufferPool.Put(b)
+}
+
 // SetDeterministic specifies whether to use deterministic serialization.
 //
 // Deterministic serialization guarantees that for a given binary, equal
@@ -71,8 +107,7 @@ func NewBuffer(buf []byte) *Buffer {
 // languages. It is not guaranteed to remain stable over time. It is unstable
 // across different builds with schema changes due to unknown fields.
 // Users who need canonical serialization (e.g., persistent storage in a
-// canonical form, fingerprinting, etc.) should define their own
-// canonicalization specification and implement their own serializer rather
+// canonical form, fingerprinting, etc.) should use MarshalCanonical rather
 // than relying on this API.
 //
 // If deterministic serialization is requested, map entries will be sorted
@@ -108,7 +143,7 @@ func (b *Buffer) Unread() []byte {
 // Marshal appends the wire-format encoding of m to the buffer.
 func (b *Buffer) Marshal(m Message) error {
 	var err error
-	b.buf, err = marshalAppend(b.buf, m, b.deterministic)
+	b.buf, err = marshalAppendParallel(b.buf, m, b.deterministic, b.parallelism)
 	return err
 }
 
@@ -116,7 +151,7 @@ func (b *Buffer) Marshal(m Message) error {
 // places the decoded results in m.
 // It does not reset m before unmarshaling.
 func (b *Buffer) Unmarshal(m Message) error {
-	err := UnmarshalMerge(b.Unread(), m)
+	err := b.unmarshalMerge(b.Unread(), m)
 	b.idx = len(b.buf)
 	return err
 }
@@ -136,6 +171,26 @@ func (*Buffer) DebugPrint(s string, b []byte) {
 	fmt.Printf("==== %s ====\n%s==== %s ====\n", s, b, s)
 }
 
+// EncodeTag appends the varint encoded tag of a field with the given
+// field number and wire type (e.g., WireBytes) to the buffer.
+func (b *Buffer) EncodeTag(fieldNum int32, wireType int) error {
+	if err := checkFieldNumber(fieldNum); err != nil {
+		return err
+	}
+	if wireType < WireVarint || wireType > WireFixed32 {
+		return fmt.Errorf("proto: invalid wire type %d", wireType)
+	}
+	b.buf = protowire.AppendTag(b.buf, protowire.Number(fieldNum), protowire.Type(wireType))
+	return nil
+}
+
+func checkFieldNumber(fieldNum int32) error {
+	if fieldNum < int32(protowire.MinValidNumber) || fieldNum > int32(protowire.MaxValidNumber) {
+		return fmt.Errorf("proto: invalid field number %d", fieldNum)
+	}
+	return nil
+}
+
 // EncodeVarint appends an unsigned varint encoding to the buffer.
 func (b *Buffer) EncodeVarint(v uint64) error {
 	b.buf = protowire.AppendVarint(b.buf, v)
@@ -181,10 +236,66 @@ func (b *Buffer) EncodeStringBytes(v string) error {
 func (b *Buffer) EncodeMessage(m Message) error {
 	var err error
 	b.buf = protowire.AppendVarint(b.buf, uint64(Size(m)))
-	b.buf, err = marshalAppend(b.buf, m, b.deterministic)
+	b.buf, err = marshalAppendParallel(b.buf, m, b.deterministic, b.parallelism)
 	return err
 }
 
+// DecodeTag consumes a field tag from the buffer,
+// returning the field number and wire type.
+func (b *Buffer) DecodeTag() (fieldNum int32, wireType int, err error) {
+	fieldNum, wireType, n, err := b.peekTag()
+	if err != nil {
+		return 0, 0, err
+	}
+	b.idx += n
+	return fieldNum, wireType, nil
+}
+
+// PeekTag returns the field number and wire type of the next field tag
+// in the buffer without consuming it.
+func (b *Buffer) PeekTag() (fieldNum int32, wireType int, err error) {
+	fieldNum, wireType, _, err = b.peekTag()
+	return fieldNum, wireType, err
+}
+
+func (b *Buffer) peekTag() (int32, int, int, error) {
+	num, typ, n := protowire.ConsumeTag(b.buf[b.idx:])
+	if n < 0 {
+		return 0, 0, 0, protowire.ParseError(n)
+	}
+	return int32(num), int(typ), n, nil
+}
+
+// SkipField consumes the value of a field with the given wire type from the
+// buffer, assuming that the field tag has already been consumed.
+// For WireStartGroup, it consumes all bytes until (and including)
+// the end group marker.
+func (b *Buffer) SkipField(wireType int) error {
+	var n int
+	switch wireType {
+	case WireVarint:
+		_, n = protowire.ConsumeVarint(b.buf[b.idx:])
+	case WireFixed32:
+		_, n = protowire.ConsumeFixed32(b.buf[b.idx:])
+	case WireFixed64:
+		_, n = protowire.ConsumeFixed64(b.buf[b.idx:])
+	case WireBytes:
+		_, n = protowire.ConsumeBytes(b.buf[b.idx:])
+	case WireStartGroup:
+		var err error
+		if _, n, err = consumeGroup(b.buf[b.idx:]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("proto: cannot skip wire type %d", wireType)
+	}
+	if n < 0 {
+		return protowire.ParseError(n)
+	}
+	b.idx += n
+	return nil
+}
+
 // DecodeVarint consumes an encoded unsigned varint from the buffer.
 func (b *Buffer) DecodeVarint() (uint64, error) {
 	v, n := protowire.ConsumeVarint(b.buf[b.idx:])
@@ -266,7 +377,7 @@ func (b *Buffer) DecodeMessage(m Message) error {
 	if err != nil {
 		return err
 	}
-	return UnmarshalMerge(v, m)
+	return b.unmarshalMerge(v, m)
 }
 
 // DecodeGroup consumes a message group from the buffer.
@@ -279,7 +390,7 @@ func (b *Buffer) DecodeGroup(m Message) error {
 		return err
 	}
 	b.idx += n
-	return UnmarshalMerge(v, m)
+	return b.unmarshalMerge(v, m)
 }
 
 // consumeGroup parses b until it finds an end group marker, returning
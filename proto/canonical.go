@@ -0,0 +1,351 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MarshalCanonical returns the canonical wire-format encoding of m,
+// which is the same for all equal messages of the same type, suitable for
+// hashing, signing, or use as a cache key.
+//
+// The canonical encoding is defined by this package as follows,
+// and is guaranteed not to change in future versions:
+//
+//   - Fields are encoded in order of their field numbers, including extension
+//     fields and unknown fields. Unknown fields with the same field number
+//     keep their relative order, and follow a known field with that number.
+//   - Unpopulated fields are not encoded. Populated fields with implicit
+//     presence (i.e., singular proto3 scalars) are non-zero, while those with
+//     explicit presence are encoded even if they have their default value.
+//   - Repeated fields are encoded packed if and only if they are declared as
+//     packed (which is the default for scalar numeric types in proto3).
+//   - Map entries are encoded in order of their keys, where false precedes
+//     true, integers are ordered numerically, and strings by their bytes.
+//     Each entry encodes its key followed by its value, even if these are
+//     the zero values.
+//   - Messages are encoded recursively in canonical form, and groups with
+//     start and end group markers.
+//   - Varints have their minimal length, and negative values of int32 and
+//     enum fields are sign-extended to 64 bits. Floating-point values are
+//     encoded bit-for-bit, except that, like Equal, all NaNs are encoded as
+//     the same quiet NaN and negative zero is encoded as positive zero.
+//   - The content of unknown fields is left as is.
+//
+// As canonical encoding is implemented using protobuf reflection, it is
+// slower than Marshal and MarshalDeterministic. Like Marshal, it reports a
+// *RequiredNotSetError if required fields are missing, along with the
+// encoding of the rest of the message.
+func MarshalCanonical(m Message) ([]byte, error) {
+	if m == nil {
+		return nil, ErrNil
+	}
+	mi := MessageV2(m)
+	mr := mi.ProtoReflect()
+	if !mr.IsValid() {
+		return nil, ErrNil
+	}
+	var e canonicalEncoder
+	n, err := e.sizeMessage(mr)
+	if err != nil {
+		return nil, err
+	}
+	b := e.appendMessage(make([]byte, 0, n))
+	return b, checkRequiredNotSet(mi)
+}
+
+// canonicalEncoder produces the canonical encoding in two passes over
+// a message: the first computes the size of every length-delimited value,
+// and the second appends the encoding, writing each length prefix directly
+// before its value. Both passes visit the messages and length-delimited
+// values in the same order, in which the first pass records the sorted
+// fields and sizes that the second consumes.
+type canonicalEncoder struct {
+	fields [][]canonicalField // fields of each message
+	sizes  []int              // size of each length-delimited value
+
+	nextFields, nextSize int // the next ones for the second pass
+}
+
+type canonicalField struct {
+	num     protowire.Number
+	fd      protoreflect.FieldDescriptor // nil for an unknown field
+	v       protoreflect.Value
+	raw     []byte     // encoding of an unknown field
+	entries []mapEntry // sorted entries of a map field
+}
+
+// canonicalFields returns the populated and unknown fields of m in order of
+// their field numbers, where unknown fields follow a known field with the
+// same number.
+func canonicalFields(m protoreflect.Message) ([]canonicalField, error) {
+	var fields []canonicalField
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fields = append(fields, canonicalField{num: fd.Number(), fd: fd, v: v})
+		return true
+	})
+	unknown := m.GetUnknown()
+	err := rangeWire(unknown, func(num protowire.Number, i, n int) {
+		fields = append(fields, canonicalField{num: num, raw: unknown[i : i+n]})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("proto: unknown fields of %v: %v", m.Descriptor().FullName(), err)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].num != fields[j].num {
+			return fields[i].num < fields[j].num
+		}
+		return fields[i].fd != nil && fields[j].fd == nil
+	})
+	return fields, nil
+}
+
+// reserveSize adds an entry to e.sizes for a length-delimited value,
+// and returns its index, so that the entry precedes those of the
+// values nested in it.
+func (e *canonicalEncoder) reserveSize() int {
+	e.sizes = append(e.sizes, 0)
+	return len(e.sizes) - 1
+}
+
+// sizeMessage returns the size of the canonical encoding of m.
+func (e *canonicalEncoder) sizeMessage(m protoreflect.Message) (int, error) {
+	fields, err := canonicalFields(m)
+	if err != nil {
+		return 0, err
+	}
+	e.fields = append(e.fields, fields)
+	n := 0
+	for i := range fields {
+		f := &fields[i]
+		if f.fd == nil {
+			n += len(f.raw)
+			continue
+		}
+		k, err := e.sizeField(f)
+		if err != nil {
+			return 0, err
+		}
+		n += k
+	}
+	return n, nil
+}
+
+func (e *canonicalEncoder) sizeField(f *canonicalField) (int, error) {
+	fd := f.fd
+	n := 0
+	switch {
+	case fd.IsMap():
+		f.entries = sortedMapEntries(fd, f.v.Map())
+		for _, ent := range f.entries {
+			i := e.reserveSize()
+			kn, err := e.sizeValue(fd.MapKey(), ent.key.Value())
+			if err != nil {
+				return 0, err
+			}
+			vn, err := e.sizeValue(fd.MapValue(), ent.val)
+			if err != nil {
+				return 0, err
+			}
+			e.sizes[i] = kn + vn
+			n += protowire.SizeTag(fd.Number()) + protowire.SizeBytes(kn+vn)
+		}
+	case fd.IsList() && fd.IsPacked():
+		list := f.v.List()
+		k := 0
+		for i := 0; i < list.Len(); i++ {
+			k += sizeCanonicalScalar(fd, list.Get(i))
+		}
+		e.sizes = append(e.sizes, k)
+		n = protowire.SizeTag(fd.Number()) + protowire.SizeBytes(k)
+	case fd.IsList():
+		list := f.v.List()
+		for i := 0; i < list.Len(); i++ {
+			k, err := e.sizeValue(fd, list.Get(i))
+			if err != nil {
+				return 0, err
+			}
+			n += k
+		}
+	default:
+		return e.sizeValue(fd, f.v)
+	}
+	return n, nil
+}
+
+// sizeValue returns the size of the tag and a single value of fd.
+func (e *canonicalEncoder) sizeValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (int, error) {
+	switch fd.Kind() {
+	case protoreflect.GroupKind:
+		n, err := e.sizeMessage(v.Message())
+		if err != nil {
+			return 0, err
+		}
+		return 2*protowire.SizeTag(fd.Number()) + n, nil
+	case protoreflect.MessageKind:
+		i := e.reserveSize()
+		n, err := e.sizeMessage(v.Message())
+		if err != nil {
+			return 0, err
+		}
+		e.sizes[i] = n
+		return protowire.SizeTag(fd.Number()) + protowire.SizeBytes(n), nil
+	case protoreflect.StringKind:
+		if enforceUTF8(fd) && !utf8.ValidString(v.String()) {
+			return 0, fmt.Errorf("proto: field %v contains invalid UTF-8", fd.FullName())
+		}
+	}
+	return protowire.SizeTag(fd.Number()) + sizeCanonicalScalar(fd, v), nil
+}
+
+// sizeCanonicalScalar returns the size of a value of fd,
+// which is not a message, without a tag.
+func sizeCanonicalScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) int {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protowire.SizeVarint(protowire.EncodeBool(v.Bool()))
+	case protoreflect.EnumKind:
+		return protowire.SizeVarint(uint64(v.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Int64Kind:
+		return protowire.SizeVarint(uint64(v.Int()))
+	case protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		return protowire.SizeVarint(protowire.EncodeZigZag(v.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		return protowire.SizeVarint(v.Uint())
+	case protoreflect.Sfixed32Kind, protoreflect.Fixed32Kind, protoreflect.FloatKind:
+		return protowire.SizeFixed32()
+	case protoreflect.Sfixed64Kind, protoreflect.Fixed64Kind, protoreflect.DoubleKind:
+		return protowire.SizeFixed64()
+	case protoreflect.StringKind:
+		return protowire.SizeBytes(len(v.String()))
+	default:
+		return protowire.SizeBytes(len(v.Bytes()))
+	}
+}
+
+// appendSize appends the next size computed by the first pass.
+func (e *canonicalEncoder) appendSize(b []byte) []byte {
+	n := e.sizes[e.nextSize]
+	e.nextSize++
+	return protowire.AppendVarint(b, uint64(n))
+}
+
+// appendMessage appends the canonical encoding of the next message,
+// which the first pass has already checked for errors.
+func (e *canonicalEncoder) appendMessage(b []byte) []byte {
+	fields := e.fields[e.nextFields]
+	e.nextFields++
+	for i := range fields {
+		f := &fields[i]
+		if f.fd == nil {
+			b = append(b, f.raw...)
+			continue
+		}
+		b = e.appendField(b, f)
+	}
+	return b
+}
+
+func (e *canonicalEncoder) appendField(b []byte, f *canonicalField) []byte {
+	fd := f.fd
+	switch {
+	case fd.IsMap():
+		for _, ent := range f.entries {
+			b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+			b = e.appendSize(b)
+			b = e.appendValue(b, fd.MapKey(), ent.key.Value())
+			b = e.appendValue(b, fd.MapValue(), ent.val)
+		}
+	case fd.IsList() && fd.IsPacked():
+		list := f.v.List()
+		b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+		b = e.appendSize(b)
+		for i := 0; i < list.Len(); i++ {
+			b = appendCanonicalScalar(b, fd, list.Get(i))
+		}
+	case fd.IsList() && fd.Message() != nil:
+		// The first pass recorded the fields of the messages.
+		for i, n := 0, f.v.List().Len(); i < n; i++ {
+			b = e.appendValue(b, fd, protoreflect.Value{})
+		}
+	case fd.IsList():
+		list := f.v.List()
+		for i := 0; i < list.Len(); i++ {
+			b = e.appendValue(b, fd, list.Get(i))
+		}
+	default:
+		b = e.appendValue(b, fd, f.v)
+	}
+	return b
+}
+
+// appendValue appends the tag and a single value of fd,
+// where v is unused for messages.
+func (e *canonicalEncoder) appendValue(b []byte, fd protoreflect.FieldDescriptor, v protoreflect.Value) []byte {
+	switch fd.Kind() {
+	case protoreflect.GroupKind:
+		b = protowire.AppendTag(b, fd.Number(), protowire.StartGroupType)
+		b = e.appendMessage(b)
+		return protowire.AppendTag(b, fd.Number(), protowire.EndGroupType)
+	case protoreflect.MessageKind:
+		b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+		b = e.appendSize(b)
+		return e.appendMessage(b)
+	}
+	b = protowire.AppendTag(b, fd.Number(), wireTypeOf(fd))
+	return appendCanonicalScalar(b, fd, v)
+}
+
+// appendCanonicalScalar appends a value of fd, which is not a message,
+// without a tag.
+func appendCanonicalScalar(b []byte, fd protoreflect.FieldDescriptor, v protoreflect.Value) []byte {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protowire.AppendVarint(b, protowire.EncodeBool(v.Bool()))
+	case protoreflect.EnumKind:
+		return protowire.AppendVarint(b, uint64(v.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Int64Kind:
+		return protowire.AppendVarint(b, uint64(v.Int()))
+	case protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		return protowire.AppendVarint(b, protowire.EncodeZigZag(v.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		return protowire.AppendVarint(b, v.Uint())
+	case protoreflect.Sfixed32Kind:
+		return protowire.AppendFixed32(b, uint32(v.Int()))
+	case protoreflect.Fixed32Kind:
+		return protowire.AppendFixed32(b, uint32(v.Uint()))
+	case protoreflect.FloatKind:
+		return protowire.AppendFixed32(b, math.Float32bits(float32(canonicalFloat(v.Float()))))
+	case protoreflect.Sfixed64Kind:
+		return protowire.AppendFixed64(b, uint64(v.Int()))
+	case protoreflect.Fixed64Kind:
+		return protowire.AppendFixed64(b, v.Uint())
+	case protoreflect.DoubleKind:
+		return protowire.AppendFixed64(b, math.Float64bits(canonicalFloat(v.Float())))
+	case protoreflect.StringKind:
+		return protowire.AppendString(b, v.String())
+	default:
+		return protowire.AppendBytes(b, v.Bytes())
+	}
+}
+
+// canonicalFloat returns f with all NaNs replaced by the same NaN and
+// negative zero replaced by positive zero, which Equal considers equal.
+func canonicalFloat(f float64) float64 {
+	switch {
+	case math.IsNaN(f):
+		return math.NaN()
+	case f == 0:
+		return 0 // positive zero
+	}
+	return f
+}
@@ -0,0 +1,315 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protopack"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func TestMarshalCanonicalMatchesDeterministic(t *testing.T) {
+	// Without extension and unknown fields, NaN payloads or negative zeros,
+	// the canonical encoding is the same as the deterministic encoding of
+	// the current implementation.
+	goTest := initGoTest(true)
+	goTest.RepeatedField = []*pb2.GoTestField{initGoTestField(), initGoTestField()}
+	goTest.Optionalgroup = initGoTest_OptionalGroup()
+	goTest.Repeatedgroup = []*pb2.GoTest_RepeatedGroup{initGoTest_RepeatedGroup()}
+	goTest.F_Int32Repeated = []int32{-1, 0, 1}
+	goTest.F_Sint64RepeatedPacked = []int64{-1, math.MinInt64, math.MaxInt64}
+	goTest.F_FloatRepeatedPacked = []float32{float32(math.Inf(-1)), 0, 1.5}
+	goTest.F_DoubleOptional = proto.Float64(0)
+
+	for _, m := range []proto.Message{
+		goTest,
+		&pb2.MoreRepeated{
+			Bools:        []bool{true, false},
+			BoolsPacked:  []bool{false, true},
+			Ints:         []int32{math.MinInt32, 7},
+			IntsPacked:   []int32{-7, math.MaxInt32},
+			Int64SPacked: []int64{1 << 40},
+			Strings:      []string{"", "x"},
+			Fixeds:       []uint32{0, math.MaxUint32},
+		},
+		&pb2.MessageWithMap{
+			NameMapping: map[int32]string{3: "c", -1: "a", 0: ""},
+			MsgMapping:  map[int64]*pb2.FloatingPoint{5: {F: proto.Float64(1)}, -5: {F: proto.Float64(0)}},
+			ByteMapping: map[bool][]byte{true: []byte("t"), false: nil},
+			StrToStr:    map[string]string{"b": "2", "a": "1", "": ""},
+		},
+		&pb2.Oneof{Union: &pb2.Oneof_FGroup{FGroup: &pb2.Oneof_F_Group{X: proto.Int32(1)}}},
+		&pb3.Message{
+			Name:        "name",
+			Hilarity:    pb3.Message_PUNS,
+			HeightInCm:  -0,
+			Data:        []byte{0},
+			ResultCount: -1,
+			Score:       float32(math.NaN()),
+			Key:         []uint64{1, 1 << 63},
+			Terrain:     map[string]*pb3.Nested{"z": {}, "a": {Bunny: "b", Cute: true}},
+			Children:    []*pb3.Message{{}, {Name: "c"}},
+		},
+	} {
+		got, err := proto.MarshalCanonical(m)
+		if err != nil {
+			t.Errorf("MarshalCanonical(%T) error: %v", m, err)
+			continue
+		}
+		want, err := proto.MarshalDeterministic(m)
+		if err != nil {
+			t.Errorf("MarshalDeterministic(%T) error: %v", m, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("MarshalCanonical(%T):\ngot:  %x\nwant: %x", m, got, want)
+		}
+	}
+}
+
+func TestMarshalCanonicalOrder(t *testing.T) {
+	newMessage := func(unknown protopack.Message, setExtensions func(m *pb2.MyMessage)) *pb2.MyMessage {
+		m := &pb2.MyMessage{Count: proto.Int32(1), Name: proto.String("n"), Bikeshed: pb2.MyMessage_BLUE.Enum()}
+		setExtensions(m)
+		proto.MessageReflect(m).SetUnknown(unknown.Marshal())
+		return m
+	}
+	more := &pb2.Ext{Data: proto.String("more")}
+	m1 := newMessage(protopack.Message{
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(2),
+		protopack.Tag{3, protopack.BytesType}, protopack.String("u3"),
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(1),
+	}, func(m *pb2.MyMessage) {
+		proto.SetExtension(m, pb2.E_Ext_Text, proto.String("text"))
+		proto.SetExtension(m, pb2.E_Ext_More, more)
+	})
+	m2 := newMessage(protopack.Message{
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(2),
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{3, protopack.BytesType}, protopack.String("u3"),
+	}, func(m *pb2.MyMessage) {
+		proto.SetExtension(m, pb2.E_Ext_More, more)
+		proto.SetExtension(m, pb2.E_Ext_Text, proto.String("text"))
+	})
+
+	want := protopack.Message{
+		protopack.Tag{1, protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{2, protopack.BytesType}, protopack.String("n"),
+		protopack.Tag{3, protopack.BytesType}, protopack.String("u3"),
+		protopack.Tag{7, protopack.VarintType}, protopack.Varint(2),
+		protopack.Tag{103, protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{1, protopack.BytesType}, protopack.String("more"),
+		},
+		protopack.Tag{104, protopack.BytesType}, protopack.String("text"),
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(2),
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(1),
+	}.Marshal()
+	for _, m := range []*pb2.MyMessage{m1, m2} {
+		got, err := proto.MarshalCanonical(m)
+		if err != nil {
+			t.Fatalf("MarshalCanonical error: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("MarshalCanonical(%v):\ngot:  %x\nwant: %x", m, got, want)
+		}
+		var rt pb2.MyMessage
+		if err := proto.Unmarshal(got, &rt); err != nil {
+			t.Fatalf("Unmarshal error: %v", err)
+		}
+		if again, err := proto.MarshalCanonical(&rt); err != nil || !bytes.Equal(again, want) {
+			t.Errorf("MarshalCanonical of unmarshaled message = %x, %v, want %x", again, err, want)
+		}
+	}
+}
+
+func TestMarshalCanonicalFloats(t *testing.T) {
+	// Messages that differ only in NaN payloads and the sign of zeros are
+	// equal, and so are their canonical encodings.
+	negZero := math.Copysign(0, -1)
+	goTestFloats := func(fs ...float32) *pb2.GoTest {
+		m := initGoTest(false)
+		m.F_FloatRepeatedPacked = fs
+		return m
+	}
+	nan1 := math.Float64frombits(0x7ff8000000000001)
+	nan2 := math.Float64frombits(0xfff0000000000042)
+	for _, pair := range [][2]proto.Message{{
+		&pb2.FloatingPoint{F: proto.Float64(negZero)},
+		&pb2.FloatingPoint{F: proto.Float64(0)},
+	}, {
+		&pb2.FloatingPoint{F: proto.Float64(nan1)},
+		&pb2.FloatingPoint{F: proto.Float64(nan2)},
+	}, {
+		goTestFloats(float32(negZero), math.Float32frombits(0x7fc00001)),
+		goTestFloats(0, math.Float32frombits(0xff800042)),
+	}, {
+		&pb3.Message{Score: math.Float32frombits(0x7fc00001)},
+		&pb3.Message{Score: math.Float32frombits(0xff800042)},
+	}, {
+		&pb2.MessageWithMap{MsgMapping: map[int64]*pb2.FloatingPoint{1: {F: proto.Float64(nan1)}, 2: {F: proto.Float64(negZero)}}},
+		&pb2.MessageWithMap{MsgMapping: map[int64]*pb2.FloatingPoint{1: {F: proto.Float64(nan2)}, 2: {F: proto.Float64(0)}}},
+	}} {
+		x, y := pair[0], pair[1]
+		if !proto.Equal(x, y) {
+			t.Errorf("Equal(%v, %v) = false, want true", x, y)
+			continue
+		}
+		bx, err := proto.MarshalCanonical(x)
+		if err != nil {
+			t.Fatalf("MarshalCanonical error: %v", err)
+		}
+		by, err := proto.MarshalCanonical(y)
+		if err != nil {
+			t.Fatalf("MarshalCanonical error: %v", err)
+		}
+		if !bytes.Equal(bx, by) {
+			t.Errorf("MarshalCanonical(%v) = %x, MarshalCanonical(%v) = %x, want equal", x, bx, y, by)
+		}
+	}
+}
+
+func TestMarshalCanonicalNested(t *testing.T) {
+	// The unknown fields of nested messages are sorted too.
+	inner := &pb3.Nested{Bunny: "b"}
+	proto.MessageReflect(inner).SetUnknown(protopack.Message{
+		protopack.Tag{9, protopack.VarintType}, protopack.Varint(9),
+		protopack.Tag{1, protopack.BytesType}, protopack.String("dup"),
+	}.Marshal())
+	m := &pb3.Message{Terrain: map[string]*pb3.Nested{"k": inner}}
+
+	want := protopack.Message{
+		protopack.Tag{10, protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{1, protopack.BytesType}, protopack.String("k"),
+			protopack.Tag{2, protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{1, protopack.BytesType}, protopack.String("b"),
+				protopack.Tag{1, protopack.BytesType}, protopack.String("dup"),
+				protopack.Tag{9, protopack.VarintType}, protopack.Varint(9),
+			},
+		},
+	}.Marshal()
+	got, err := proto.MarshalCanonical(m)
+	if err != nil {
+		t.Fatalf("MarshalCanonical error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("MarshalCanonical:\ngot:  %x\nwant: %x", got, want)
+	}
+}
+
+func TestMarshalCanonicalErrors(t *testing.T) {
+	for _, m := range []proto.Message{nil, (*pb3.Message)(nil)} {
+		if _, err := proto.MarshalCanonical(m); err != proto.ErrNil {
+			t.Errorf("MarshalCanonical(%#v) error = %v, want ErrNil", m, err)
+		}
+	}
+
+	b, err := proto.MarshalCanonical(&pb2.InnerMessage{Port: proto.Int32(1)})
+	if !isRequiredNotSetError(err) {
+		t.Errorf("MarshalCanonical with missing required field: error = %v, want RequiredNotSetError", err)
+	}
+	if want := (protopack.Message{protopack.Tag{2, protopack.VarintType}, protopack.Varint(1)}).Marshal(); !bytes.Equal(b, want) {
+		t.Errorf("MarshalCanonical with missing required field = %x, want %x", b, want)
+	}
+
+	if _, err := proto.MarshalCanonical(&pb3.Message{Name: "\xff"}); err == nil {
+		t.Errorf("MarshalCanonical with invalid UTF-8 succeeded, want error")
+	}
+
+	m := &pb3.Message{}
+	proto.MessageReflect(m).SetUnknown([]byte{0x80})
+	if _, err := proto.MarshalCanonical(m); err == nil {
+		t.Errorf("MarshalCanonical with malformed unknown fields succeeded, want error")
+	}
+}
+
+func TestMarshalCanonicalEditionsUTF8(t *testing.T) {
+	// An editions file with a string field that is validated by default,
+	// and one that is not.
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("canonical_editions.proto"),
+		Package: proto.String("canonical.test"),
+		Syntax:  proto.String("editions"),
+		Edition: descriptorpb.Edition_EDITION_2023.Enum(),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("M"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:   proto.String("verified"),
+				Number: proto.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}, {
+				Name:   proto.String("unverified"),
+				Number: proto.Int32(2),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Options: &descriptorpb.FieldOptions{
+					Features: &descriptorpb.FeatureSet{
+						Utf8Validation: descriptorpb.FeatureSet_NONE.Enum(),
+					},
+				},
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile error: %v", err)
+	}
+	md := fd.Messages().Get(0)
+	for _, tt := range []struct {
+		field   protoreflect.Name
+		wantErr bool
+	}{
+		{"verified", true},
+		{"unverified", false},
+	} {
+		m := dynamicpb.NewMessage(md)
+		m.Set(md.Fields().ByName(tt.field), protoreflect.ValueOfString("\xff"))
+		_, err := proto.MarshalCanonical(m)
+		if gotErr := err != nil; gotErr != tt.wantErr {
+			t.Errorf("MarshalCanonical with invalid UTF-8 in %v: error = %v, want error: %v", tt.field, err, tt.wantErr)
+		}
+	}
+}
+
+func BenchmarkMarshalCanonicalDeep(b *testing.B) {
+	m := &pb3.Message{Name: "leaf", Data: make([]byte, 64<<10)}
+	for i := 0; i < 100; i++ {
+		m = &pb3.Message{Children: []*pb3.Message{m}}
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := proto.MarshalCanonical(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalDeterministic(t *testing.T) {
+	m := &pb3.Message{Terrain: map[string]*pb3.Nested{"c": {}, "a": {}, "b": {}}}
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(m); err != nil {
+		t.Fatalf("Buffer.Marshal error: %v", err)
+	}
+	got, err := proto.MarshalDeterministic(m)
+	if err != nil {
+		t.Fatalf("MarshalDeterministic error: %v", err)
+	}
+	if !bytes.Equal(got, buf.Bytes()) {
+		t.Errorf("MarshalDeterministic = %x, want %x", got, buf.Bytes())
+	}
+	if b, err := proto.MarshalDeterministic(&pb3.Message{}); b == nil || err != nil {
+		t.Errorf("MarshalDeterministic(empty) = %#v, %v, want non-nil empty bytes", b, err)
+	}
+}
@@ -18,6 +18,15 @@ func SetDefaults(m Message) {
 }
 
 func setDefaults(m protoreflect.Message) {
+	rangeMessages(m, func(m protoreflect.Message) bool {
+		setDefaultFields(m)
+		return true
+	})
+}
+
+// setDefaultFields sets the unpopulated scalar fields of m, but not of
+// the messages embedded in it, to their default values.
+func setDefaultFields(m protoreflect.Message) {
 	fds := m.Descriptor().Fields()
 	for i := 0; i < fds.Len(); i++ {
 		fd := fds.Get(i)
@@ -32,32 +41,4 @@ func setDefaults(m protoreflect.Message) {
 			continue
 		}
 	}
-
-	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
-		switch {
-		// Handle singular message.
-		case fd.Cardinality() != protoreflect.Repeated:
-			if fd.Message() != nil {
-				setDefaults(m.Get(fd).Message())
-			}
-		// Handle list of messages.
-		case fd.IsList():
-			if fd.Message() != nil {
-				ls := m.Get(fd).List()
-				for i := 0; i < ls.Len(); i++ {
-					setDefaults(ls.Get(i).Message())
-				}
-			}
-		// Handle map of messages.
-		case fd.IsMap():
-			if fd.MapValue().Message() != nil {
-				ms := m.Get(fd).Map()
-				ms.Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
-					setDefaults(v.Message())
-					return true
-				})
-			}
-		}
-		return true
-	})
 }
@@ -0,0 +1,348 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ExtractField returns the values of the field at path in the wire-format
+// message b of the type described by md. It only decodes the fields along
+// the path, and skips over all others without allocating.
+//
+// The path is a sequence of field names separated by dots
+// (e.g., "header.tenant_id"), where all but the last refer to message fields.
+// Extension fields are referred to by their full name in parentheses
+// (e.g., "header.(my.pkg.ext)"). Map fields are not supported.
+//
+// The values are the ones that Unmarshal would produce for the field:
+// a singular field has at most one value, while a repeated field may have
+// several. If the path traverses repeated message fields, the values for
+// all of their elements are returned in order. Values of message fields are
+// newly allocated messages, values of bytes fields alias b, values of string
+// fields are copied out of b, and all other values are decoded without
+// allocation. Values that a closed enum does not define are returned like
+// any other, since Unmarshal also stores them in the field rather than among
+// the unknown fields.
+//
+// Validation is limited to the fields along the path: it reports an error
+// if these are malformed, but not if the rest of b is semantically invalid
+// (e.g., has invalid UTF-8 in other string fields).
+func ExtractField(b []byte, md protoreflect.MessageDescriptor, path string) ([]protoreflect.Value, error) {
+	fds, err := resolveFieldPath(md, path)
+	if err != nil {
+		return nil, err
+	}
+	return extractField(b, fds, nil)
+}
+
+// resolveFieldPath returns the descriptors for the fields named by path,
+// starting with a field of md.
+func resolveFieldPath(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("proto: invalid field path %q: %s", path, fmt.Sprintf(format, args...))
+	}
+	var fds []protoreflect.FieldDescriptor
+	for s := path; ; {
+		var fd protoreflect.FieldDescriptor
+		if strings.HasPrefix(s, "(") {
+			i := strings.IndexByte(s, ')')
+			if i < 0 {
+				return nil, errorf("missing closing parenthesis")
+			}
+			var err error
+			if fd, err = findExtensionField(md, protoreflect.FullName(s[1:i])); err != nil {
+				return nil, errorf("%v", err)
+			}
+			s = s[i+1:]
+		} else {
+			i := strings.IndexByte(s, '.')
+			if i < 0 {
+				i = len(s)
+			}
+			name := protoreflect.Name(s[:i])
+			if fd = md.Fields().ByName(name); fd == nil {
+				return nil, errorf("message %v has no field %q", md.FullName(), name)
+			}
+			s = s[i:]
+		}
+		if fd.IsMap() {
+			return nil, errorf("map field %v is not supported", fd.FullName())
+		}
+		fds = append(fds, fd)
+		if s == "" {
+			return fds, nil
+		}
+		if s[0] != '.' {
+			return nil, errorf("unexpected %q after field %v", s, fd.FullName())
+		}
+		if fd.Message() == nil {
+			return nil, errorf("field %v is not a message", fd.FullName())
+		}
+		md = fd.Message()
+		s = s[1:]
+	}
+}
+
+// findExtensionField returns the descriptor of the extension of md with the
+// given full name in the global registry.
+func findExtensionField(md protoreflect.MessageDescriptor, name protoreflect.FullName) (protoreflect.FieldDescriptor, error) {
+	xt, err := protoregistry.GlobalTypes.FindExtensionByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown extension %v", name)
+	}
+	fd := xt.TypeDescriptor()
+	if fd.ContainingMessage().FullName() != md.FullName() {
+		return nil, fmt.Errorf("extension %v does not extend %v", name, md.FullName())
+	}
+	return fd, nil
+}
+
+// extractField appends the values of the field at path fds in the message b
+// to out.
+func extractField(b []byte, fds []protoreflect.FieldDescriptor, out []protoreflect.Value) ([]protoreflect.Value, error) {
+	fd := fds[0]
+	od := fd.ContainingOneof()
+	if od != nil && od.IsSynthetic() {
+		od = nil
+	}
+	start := len(out) // index of the first value from b in out
+	var merged []byte // concatenation of the values of a singular message field
+	var nmerged int
+	for len(b) > 0 {
+		num, wtyp, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return out, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num != fd.Number() {
+			if od != nil && od.Fields().ByNumber(num) != nil {
+				// Another member of the oneof replaces the value of fd.
+				out, merged, nmerged = out[:start], nil, 0
+			}
+			n = protowire.ConsumeFieldValue(num, wtyp, b)
+			if n < 0 {
+				return out, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		var v []byte // value of a message field
+		switch {
+		case fd.Kind() == protoreflect.MessageKind && wtyp == protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		case fd.Kind() == protoreflect.GroupKind && wtyp == protowire.StartGroupType:
+			v, n = protowire.ConsumeGroup(num, b)
+		case fd.Message() == nil && wtyp == wireTypeOf(fd):
+			val, n, err := unmarshalScalar(b, wtyp, fd)
+			if err != nil {
+				return out, err
+			}
+			if !fd.IsList() {
+				out = out[:start]
+			}
+			out = append(out, val)
+			b = b[n:]
+			continue
+		case fd.IsList() && isPackable(fd) && wtyp == protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+			if n < 0 {
+				return out, protowire.ParseError(n)
+			}
+			for len(v) > 0 {
+				val, k, err := unmarshalScalar(v, wireTypeOf(fd), fd)
+				if err != nil {
+					return out, err
+				}
+				out = append(out, val)
+				v = v[k:]
+			}
+			b = b[n:]
+			continue
+		default:
+			// A value with the wrong wire type is an unknown field.
+			if n = protowire.ConsumeFieldValue(num, wtyp, b); n < 0 {
+				return out, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		if n < 0 {
+			return out, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch {
+		case fd.IsList() && len(fds) > 1:
+			var err error
+			if out, err = extractField(v, fds[1:], out); err != nil {
+				return out, err
+			}
+		case fd.IsList():
+			m, err := unmarshalExtracted(v, fd.Message())
+			if err != nil {
+				return out, err
+			}
+			out = append(out, protoreflect.ValueOfMessage(m))
+		default:
+			// Multiple values of a singular message field are merged,
+			// which is equivalent to concatenating their encodings.
+			switch nmerged {
+			case 0:
+				merged = v
+			case 1:
+				merged = append(append([]byte(nil), merged...), v...)
+			default:
+				merged = append(merged, v...)
+			}
+			nmerged++
+		}
+	}
+	if nmerged == 0 {
+		return out, nil
+	}
+	if len(fds) > 1 {
+		return extractField(merged, fds[1:], out)
+	}
+	m, err := unmarshalExtracted(merged, fd.Message())
+	if err != nil {
+		return out, err
+	}
+	return append(out, protoreflect.ValueOfMessage(m)), nil
+}
+
+// unmarshalExtracted returns a new message of the type described by md
+// decoded from b, using the generated type if it is registered.
+func unmarshalExtracted(b []byte, md protoreflect.MessageDescriptor) (protoreflect.Message, error) {
+	var m protoreflect.Message
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err == nil {
+		m = mt.New()
+	} else {
+		m = dynamicpb.NewMessage(md)
+	}
+	err := protoV2.UnmarshalOptions{AllowPartial: true}.Unmarshal(b, m.Interface())
+	return m, err
+}
+
+// unmarshalScalar parses a single scalar value of wire type wtyp from the
+// start of b, returning the value and the number of bytes consumed.
+func unmarshalScalar(b []byte, wtyp protowire.Type, fd protoreflect.FieldDescriptor) (protoreflect.Value, int, error) {
+	switch wtyp {
+	case protowire.VarintType:
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return protoreflect.Value{}, 0, protowire.ParseError(n)
+		}
+		switch fd.Kind() {
+		case protoreflect.BoolKind:
+			return protoreflect.ValueOfBool(v != 0), n, nil
+		case protoreflect.EnumKind:
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(int32(v))), n, nil
+		case protoreflect.Int32Kind:
+			return protoreflect.ValueOfInt32(int32(v)), n, nil
+		case protoreflect.Sint32Kind:
+			return protoreflect.ValueOfInt32(int32(protowire.DecodeZigZag(v & math.MaxUint32))), n, nil
+		case protoreflect.Uint32Kind:
+			return protoreflect.ValueOfUint32(uint32(v)), n, nil
+		case protoreflect.Int64Kind:
+			return protoreflect.ValueOfInt64(int64(v)), n, nil
+		case protoreflect.Sint64Kind:
+			return protoreflect.ValueOfInt64(protowire.DecodeZigZag(v)), n, nil
+		case protoreflect.Uint64Kind:
+			return protoreflect.ValueOfUint64(v), n, nil
+		}
+	case protowire.Fixed32Type:
+		v, n := protowire.ConsumeFixed32(b)
+		if n < 0 {
+			return protoreflect.Value{}, 0, protowire.ParseError(n)
+		}
+		switch fd.Kind() {
+		case protoreflect.Fixed32Kind:
+			return protoreflect.ValueOfUint32(v), n, nil
+		case protoreflect.Sfixed32Kind:
+			return protoreflect.ValueOfInt32(int32(v)), n, nil
+		case protoreflect.FloatKind:
+			return protoreflect.ValueOfFloat32(math.Float32frombits(v)), n, nil
+		}
+	case protowire.Fixed64Type:
+		v, n := protowire.ConsumeFixed64(b)
+		if n < 0 {
+			return protoreflect.Value{}, 0, protowire.ParseError(n)
+		}
+		switch fd.Kind() {
+		case protoreflect.Fixed64Kind:
+			return protoreflect.ValueOfUint64(v), n, nil
+		case protoreflect.Sfixed64Kind:
+			return protoreflect.ValueOfInt64(int64(v)), n, nil
+		case protoreflect.DoubleKind:
+			return protoreflect.ValueOfFloat64(math.Float64frombits(v)), n, nil
+		}
+	case protowire.BytesType:
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protoreflect.Value{}, 0, protowire.ParseError(n)
+		}
+		v = v[:len(v):len(v)]
+		switch fd.Kind() {
+		case protoreflect.BytesKind:
+			return protoreflect.ValueOfBytes(v), n, nil
+		case protoreflect.StringKind:
+			if enforceUTF8(fd) && !utf8.Valid(v) {
+				return protoreflect.Value{}, 0, errInvalidUTF8
+			}
+			return protoreflect.ValueOfString(string(v)), n, nil
+		}
+	}
+	return protoreflect.Value{}, 0, errors.New("proto: invalid wire type for field " + string(fd.FullName()))
+}
+
+// wireTypeOf returns the wire type of a non-packed value of fd.
+func wireTypeOf(fd protoreflect.FieldDescriptor) protowire.Type {
+	switch fd.Kind() {
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return protowire.Fixed32Type
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return protowire.Fixed64Type
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind:
+		return protowire.BytesType
+	case protoreflect.GroupKind:
+		return protowire.StartGroupType
+	default:
+		return protowire.VarintType
+	}
+}
+
+// isPackable reports whether values of fd may appear in packed form.
+func isPackable(fd protoreflect.FieldDescriptor) bool {
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		return false
+	}
+	return fd.IsList()
+}
+
+var errInvalidUTF8 = errors.New("proto: string field contains invalid UTF-8")
+
+// enforceUTF8 reports whether the values of the string field fd must be
+// valid UTF-8, as Unmarshal determines it.
+func enforceUTF8(fd protoreflect.FieldDescriptor) bool {
+	if fd.Syntax() == protoreflect.Editions {
+		if fd, ok := fd.(interface{ EnforceUTF8() bool }); ok {
+			return fd.EnforceUTF8()
+		}
+	}
+	return fd.Syntax() == protoreflect.Proto3
+}
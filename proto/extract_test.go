@@ -0,0 +1,270 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+// unmarshaledFieldValues returns the values of the field at path in m,
+// traversing repeated fields along the way.
+func unmarshaledFieldValues(m protoreflect.Message, path string) []protoreflect.Value {
+	name, rest := path, ""
+	if i := strings.IndexByte(path, '.'); i >= 0 {
+		name, rest = path[:i], path[i+1:]
+	}
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+	var vs []protoreflect.Value
+	switch {
+	case fd.IsList():
+		for i, list := 0, m.Get(fd).List(); i < list.Len(); i++ {
+			vs = append(vs, list.Get(i))
+		}
+	case m.Has(fd):
+		vs = append(vs, m.Get(fd))
+	}
+	if rest == "" {
+		return vs
+	}
+	var out []protoreflect.Value
+	for _, v := range vs {
+		out = append(out, unmarshaledFieldValues(v.Message(), rest)...)
+	}
+	return out
+}
+
+func TestExtractField(t *testing.T) {
+	mm := &pb2.MyMessage{
+		Count:     proto.Int32(42),
+		Name:      proto.String("header"),
+		Pet:       []string{"horsey", "bunny"},
+		Inner:     &pb2.InnerMessage{Host: proto.String("h"), Port: proto.Int32(1)},
+		Others:    []*pb2.OtherMessage{{Key: proto.Int64(1), Inner: &pb2.InnerMessage{Host: proto.String("a")}}, {Value: []byte("v")}, {Key: proto.Int64(3)}},
+		Somegroup: &pb2.MyMessage_SomeGroup{GroupField: proto.Int32(3)},
+		RepBytes:  [][]byte{[]byte("x"), nil},
+		Bikeshed:  pb2.MyMessage_GREEN.Enum(),
+	}
+	mm2 := &pb2.MyMessage{
+		Count:  proto.Int32(7),
+		Inner:  &pb2.InnerMessage{Host: proto.String("h2"), Connected: proto.Bool(true)},
+		Others: []*pb2.OtherMessage{{Key: proto.Int64(4)}},
+	}
+	m3 := &pb3.Message{
+		Name:     "Rabbit",
+		Key:      []uint64{1, 2, 1 << 40},
+		Nested:   &pb3.Nested{Bunny: "Monty"},
+		RFunny:   []pb3.Message_Humour{pb3.Message_PUNS, pb3.Message_SLAPSTICK},
+		Children: []*pb3.Message{{Name: "a", Key: []uint64{5}}, {Name: "b", Nested: &pb3.Nested{Cute: true}}},
+	}
+
+	tests := []struct {
+		msgs  []proto.Message // concatenated to form the input
+		paths []string
+	}{{
+		msgs:  []proto.Message{mm},
+		paths: []string{"count", "name", "quote", "pet", "inner", "inner.host", "inner.port", "others", "others.key", "others.value", "others.inner.host", "somegroup.group_field", "rep_bytes", "bikeshed"},
+	}, {
+		// Singular fields are overwritten or merged, repeated fields are appended.
+		msgs:  []proto.Message{mm, mm2},
+		paths: []string{"count", "inner", "inner.host", "inner.connected", "others.key"},
+	}, {
+		msgs:  []proto.Message{m3, m3},
+		paths: []string{"name", "key", "nested.bunny", "r_funny", "children.name", "children.key", "children.nested.cute"},
+	}, {
+		msgs:  []proto.Message{&pb2.Communique{Union: &pb2.Communique_Number{Number: 5}}, &pb2.Communique{Union: &pb2.Communique_Msg{Msg: &pb2.Strings{StringField: proto.String("s")}}}},
+		paths: []string{"number", "msg", "msg.string_field"},
+	}}
+	for _, tt := range tests {
+		var b []byte
+		for _, m := range tt.msgs {
+			mb, err := proto.Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			b = append(b, mb...)
+		}
+		m := proto.Clone(tt.msgs[0])
+		if err := proto.Unmarshal(b, m); err != nil {
+			t.Fatalf("Unmarshal error: %v", err)
+		}
+		mr := proto.MessageReflect(m)
+		for _, path := range tt.paths {
+			got, err := proto.ExtractField(b, mr.Descriptor(), path)
+			if err != nil {
+				t.Errorf("ExtractField(%v, %q) error: %v", mr.Descriptor().FullName(), path, err)
+				continue
+			}
+			want := unmarshaledFieldValues(mr, path)
+			if !equalValues(got, want) {
+				t.Errorf("ExtractField(%v, %q) = %v, want %v", mr.Descriptor().FullName(), path, got, want)
+			}
+		}
+	}
+}
+
+func equalValues(x, y []protoreflect.Value) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if mx, ok := x[i].Interface().(protoreflect.Message); ok {
+			my, ok := y[i].Interface().(protoreflect.Message)
+			if !ok || !proto.Equal(proto.MessageV1(mx.Interface()), proto.MessageV1(my.Interface())) {
+				return false
+			}
+			continue
+		}
+		if !x[i].Equal(y[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExtractFieldExtension(t *testing.T) {
+	m := &pb2.MyMessage{Count: proto.Int32(1)}
+	if err := proto.SetExtension(m, pb2.E_Ext_More, &pb2.Ext{Data: proto.String("ext")}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	md := proto.MessageReflect(m).Descriptor()
+	got, err := proto.ExtractField(b, md, "(proto2_test.Ext.more).data")
+	if err != nil {
+		t.Fatalf("ExtractField error: %v", err)
+	}
+	if len(got) != 1 || got[0].String() != "ext" {
+		t.Errorf("ExtractField = %v, want [ext]", got)
+	}
+}
+
+func TestExtractFieldErrors(t *testing.T) {
+	md2 := proto.MessageReflect(new(pb2.MyMessage)).Descriptor()
+	md3 := proto.MessageReflect(new(pb3.Message)).Descriptor()
+	for _, tt := range []struct {
+		md   protoreflect.MessageDescriptor
+		path string
+		want string
+	}{
+		{md2, "", `has no field ""`},
+		{md2, "nope", `has no field "nope"`},
+		{md2, "count.x", "is not a message"},
+		{md2, "inner.", `has no field ""`},
+		{md2, "inner..host", `has no field ""`},
+		{md2, "(proto2_test.Ext.more", "missing closing parenthesis"},
+		{md2, "(proto2_test.no_such_ext)", "unknown extension"},
+		{md2, "(proto2_test.Ext.more)x", "unexpected"},
+		{md3, "(proto2_test.Ext.more)", "does not extend"},
+		{md3, "terrain.value", "is not supported"},
+	} {
+		_, err := proto.ExtractField(nil, tt.md, tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ExtractField(%v, %q) error = %v, want %q", tt.md.FullName(), tt.path, err, tt.want)
+		}
+	}
+
+	for _, b := range []string{
+		"\x0a\x05a",         // truncated name
+		"\x32\x03\x0a\x05a", // truncated nested.bunny
+		"\x32\x02\x0a\x01",  // nested message length mismatch
+		"\x32\x03\x0a\x01\xff",
+	} {
+		if _, err := proto.ExtractField([]byte(b), md3, "nested.bunny"); err == nil {
+			t.Errorf("ExtractField(%q, nested.bunny) succeeded, want error", b)
+		}
+	}
+}
+
+func TestExtractFieldStringCopied(t *testing.T) {
+	b, err := proto.Marshal(&pb3.Message{Name: "Rabbit", Data: []byte("data")})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	md := proto.MessageReflect(&pb3.Message{}).Descriptor()
+	name, err := proto.ExtractField(b, md, "name")
+	if err != nil {
+		t.Fatalf("ExtractField error: %v", err)
+	}
+	data, err := proto.ExtractField(b, md, "data")
+	if err != nil {
+		t.Fatalf("ExtractField error: %v", err)
+	}
+	for i := range b {
+		b[i] = 'x'
+	}
+	if got := name[0].String(); got != "Rabbit" {
+		t.Errorf("string value after modifying the input = %q, want %q", got, "Rabbit")
+	}
+	if got := string(data[0].Bytes()); got != "xxxx" {
+		t.Errorf("bytes value after modifying the input = %q, want it to alias the input", got)
+	}
+}
+
+func TestExtractFieldUndefinedEnum(t *testing.T) {
+	// Values that an enum does not define are returned as they are, closed
+	// enum or not, in agreement with Unmarshal.
+	tests := []struct {
+		m    proto.Message
+		path string
+		b    string
+		want []protoreflect.EnumNumber
+	}{
+		{&pb2.MyMessage{}, "bikeshed", "\x08\x01\x38\x07", []protoreflect.EnumNumber{7}},
+		{&pb2.MyMessage{}, "bikeshed", "\x08\x01\x38\x01\x38\x07", []protoreflect.EnumNumber{7}},
+		{&pb2.MyMessage{}, "bikeshed", "\x08\x01\x38\x07\x38\x01", []protoreflect.EnumNumber{1}},
+		{&pb3.Message{}, "hilarity", "\x10\x07", []protoreflect.EnumNumber{7}},
+		{&pb3.Message{}, "r_funny", "\x82\x01\x03\x01\x07\x02", []protoreflect.EnumNumber{1, 7, 2}},
+	}
+	for _, tt := range tests {
+		md := proto.MessageReflect(tt.m).Descriptor()
+		got, err := proto.ExtractField([]byte(tt.b), md, tt.path)
+		if err != nil {
+			t.Errorf("ExtractField(%q, %q) error: %v", tt.b, tt.path, err)
+			continue
+		}
+		var want []protoreflect.Value
+		for _, n := range tt.want {
+			want = append(want, protoreflect.ValueOfEnum(n))
+		}
+		if !equalValues(got, want) {
+			t.Errorf("ExtractField(%q, %q) = %v, want %v", tt.b, tt.path, got, want)
+		}
+		if err := proto.Unmarshal([]byte(tt.b), tt.m); err != nil {
+			t.Fatalf("Unmarshal error: %v", err)
+		}
+		if unmarshaled := unmarshaledFieldValues(proto.MessageReflect(tt.m), tt.path); !equalValues(unmarshaled, want) {
+			t.Errorf("Unmarshal(%q) field %q = %v, want %v", tt.b, tt.path, unmarshaled, want)
+		}
+	}
+}
+
+func TestExtractFieldAllocs(t *testing.T) {
+	m := &pb3.Message{Name: "Rabbit", Nested: &pb3.Nested{Bunny: "Monty"}}
+	for i := 0; i < 100; i++ {
+		m.Children = append(m.Children, &pb3.Message{Name: "child", Key: []uint64{1, 2, 3}})
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	md := proto.MessageReflect(m).Descriptor()
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := proto.ExtractField(b, md, "nested.cute"); err != nil {
+			t.Errorf("ExtractField error: %v", err)
+		}
+	})
+	if allocs > 2 {
+		t.Errorf("ExtractField allocs = %v, want at most 2", allocs)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protorand"
+	protoV2 "google.golang.org/protobuf/proto"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+// fuzzMessages returns new messages of the types that the fuzz targets
+// decode each input as.
+func fuzzMessages() []proto.Message {
+	return []proto.Message{
+		new(pb2.GoTest),
+		new(pb2.MyMessage),
+		new(pb2.MessageWithMap),
+		new(pb2.Oneof),
+		new(pb2.Communique),
+		new(pb3.Message),
+	}
+}
+
+// newLike returns a new empty message of the same type as m.
+func newLike(m proto.Message) proto.Message {
+	return proto.MessageV1(proto.MessageReflect(m).New().Interface())
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, m := range []proto.Message{initGoTest(true), newTestMessage()} {
+		b, err := proto.Marshal(m)
+		if err != nil {
+			f.Fatalf("Marshal error: %v", err)
+		}
+		f.Add(b)
+	}
+	for _, g := range goldenMessages {
+		b, err := proto.Marshal(g.m)
+		if err != nil {
+			f.Fatalf("Marshal error: %v", err)
+		}
+		f.Add(b)
+	}
+	g := protorand.New(1)
+	for _, m := range fuzzMessages() {
+		g.Fill(m)
+		b, err := proto.Marshal(m)
+		if err != nil {
+			f.Fatalf("Marshal error: %v", err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		for _, m1 := range fuzzMessages() {
+			if err := proto.Unmarshal(b, m1); err != nil {
+				continue
+			}
+			// A message field with the wrong wire type is kept as an unknown
+			// field, but may leave behind an empty message whose required
+			// fields are not set, so the round trip allows partial messages.
+			b1, err := protoV2.MarshalOptions{AllowPartial: true}.Marshal(proto.MessageV2(m1))
+			if err != nil {
+				t.Fatalf("Marshal(%T) error: %v", m1, err)
+			}
+			if n := proto.Size(m1); n != len(b1) {
+				t.Errorf("Size(%T) = %d, but Marshal output has length %d", m1, n, len(b1))
+			}
+			m2 := newLike(m1)
+			if err := (protoV2.UnmarshalOptions{AllowPartial: true}).Unmarshal(b1, proto.MessageV2(m2)); err != nil {
+				t.Fatalf("Unmarshal(Marshal(%T)) error: %v", m1, err)
+			}
+			if !proto.Equal(m1, m2) {
+				t.Errorf("Unmarshal(Marshal(%T)) != Unmarshal(input):\ngot:  %v\nwant: %v", m1, m2, m1)
+			}
+		}
+	})
+}
+
+func FuzzUnmarshalText(f *testing.F) {
+	for _, tt := range unmarshalTextTests {
+		f.Add(tt.in)
+	}
+	for _, g := range goldenMessages {
+		f.Add(g.t)
+	}
+	g := protorand.New(1)
+	for _, m := range fuzzMessages() {
+		g.Fill(m)
+		f.Add((&proto.TextMarshaler{}).Text(m))
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, m1 := range fuzzMessages() {
+			if err := proto.UnmarshalText(s, m1); err != nil {
+				continue
+			}
+			for _, s1 := range []string{(&proto.TextMarshaler{}).Text(m1), (&proto.TextMarshaler{Compact: true}).Text(m1)} {
+				m2 := newLike(m1)
+				if err := proto.UnmarshalText(s1, m2); err != nil {
+					t.Fatalf("UnmarshalText(%q) error: %v", s1, err)
+				}
+				if !proto.Equal(m1, m2) {
+					t.Errorf("UnmarshalText(%q) != UnmarshalText(input):\ngot:  %v\nwant: %v", s1, m2, m1)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"crypto/sha256"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Hash returns a 64-bit hash of m, such that messages that are Equal have
+// the same hash. It is suitable for hash tables and deduplication,
+// but not as a collision-resistant fingerprint; use HashSHA256 for that.
+//
+// The hash depends only on the full name of the type of m and on its
+// contents as reported by protobuf reflection: it does not depend on the
+// order of map entries, nor on the order of unknown fields with different
+// field numbers. It is stable across processes and is guaranteed not to
+// change in future versions. Like Equal, it considers all NaNs equal,
+// and positive and negative zero equal.
+//
+// Unlike Equal, Hash does not distinguish an invalid message,
+// such as a typed nil pointer, from an empty message of the same type.
+func Hash(m Message) uint64 {
+	h := fnv.New64a()
+	writeHash(h, m)
+	return h.Sum64()
+}
+
+// HashSHA256 is like Hash, but returns a SHA-256 hash of m.
+func HashSHA256(m Message) [sha256.Size]byte {
+	h := sha256.New()
+	writeHash(h, m)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+func writeHash(h hash.Hash, m Message) {
+	if m == nil {
+		return
+	}
+	mr := MessageReflect(m)
+	w := hashWriter{h: h}
+	w.buf = protowire.AppendString(w.buf, string(mr.Descriptor().FullName()))
+	w.writeMessage(mr)
+	w.flush()
+}
+
+// hashWriter writes the hashed representation of messages to h.
+//
+// The representation of a message is a sequence of its populated and
+// unknown fields in the order of MarshalCanonical, followed by a zero
+// varint. A known field is written as a varint of its field number
+// shifted left by one, followed by its value, which for repeated and map
+// fields is preceded by the number of elements or entries. An unknown field
+// is written as a varint of its field number shifted left by one with the
+// lowest bit set, followed by the length-prefixed bytes of the field.
+// As the descriptors of the fields determine the types of their values,
+// the representation is unambiguous.
+type hashWriter struct {
+	h   hash.Hash
+	buf []byte
+}
+
+func (w *hashWriter) flush() {
+	w.h.Write(w.buf)
+	w.buf = w.buf[:0]
+}
+
+func (w *hashWriter) writeMessage(m protoreflect.Message) {
+	fields, err := canonicalFields(m)
+	if err != nil {
+		// Hash malformed unknown fields as a whole.
+		fields = nil
+		m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			fields = append(fields, canonicalField{num: fd.Number(), fd: fd, v: v})
+			return true
+		})
+		sort.Slice(fields, func(i, j int) bool { return fields[i].num < fields[j].num })
+		fields = append(fields, canonicalField{raw: m.GetUnknown()})
+	}
+	for _, f := range fields {
+		if f.fd == nil {
+			w.buf = protowire.AppendVarint(w.buf, uint64(f.num)<<1|1)
+			w.buf = protowire.AppendBytes(w.buf, f.raw)
+			continue
+		}
+		w.buf = protowire.AppendVarint(w.buf, uint64(f.num)<<1)
+		switch fd := f.fd; {
+		case fd.IsMap():
+			entries := sortedMapEntries(fd, f.v.Map())
+			w.buf = protowire.AppendVarint(w.buf, uint64(len(entries)))
+			for _, e := range entries {
+				w.writeValue(fd.MapKey(), e.key.Value())
+				w.writeValue(fd.MapValue(), e.val)
+			}
+		case fd.IsList():
+			list := f.v.List()
+			w.buf = protowire.AppendVarint(w.buf, uint64(list.Len()))
+			for i := 0; i < list.Len(); i++ {
+				w.writeValue(fd, list.Get(i))
+			}
+		default:
+			w.writeValue(fd, f.v)
+		}
+		if len(w.buf) >= 4096 {
+			w.flush()
+		}
+	}
+	w.buf = protowire.AppendVarint(w.buf, 0)
+}
+
+// writeValue writes a single value of fd.
+func (w *hashWriter) writeValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		w.writeMessage(v.Message())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		w.buf = protowire.AppendFixed64(w.buf, math.Float64bits(canonicalFloat(v.Float())))
+	default:
+		w.buf = appendCanonicalScalar(w.buf, fd, v)
+	}
+}
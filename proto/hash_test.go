@@ -0,0 +1,151 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/testing/protopack"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func withUnknown(m proto.Message, raw protopack.Message) proto.Message {
+	proto.MessageReflect(m).SetUnknown(raw.Marshal())
+	return m
+}
+
+func toDynamic(t *testing.T, m proto.Message) proto.Message {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	dyn := proto.MessageV1(dynamicpb.NewMessage(proto.MessageReflect(m).Descriptor()))
+	if err := proto.Unmarshal(b, dyn); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	return dyn
+}
+
+func TestHashEqual(t *testing.T) {
+	ext := &pb2.MyMessage{Count: proto.Int32(1)}
+	if err := proto.SetExtension(ext, pb2.E_Ext_More, &pb2.Ext{Data: proto.String("x")}); err != nil {
+		t.Fatal(err)
+	}
+	negZero := math.Copysign(0, -1)
+	u1 := protopack.Message{
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{101, protopack.BytesType}, protopack.String("a"),
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(2),
+	}
+	u2 := protopack.Message{
+		protopack.Tag{101, protopack.BytesType}, protopack.String("a"),
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(2),
+	}
+	u3 := protopack.Message{
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(2),
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{101, protopack.BytesType}, protopack.String("a"),
+	}
+
+	// Each group holds messages that are equal to each other,
+	// but not to those in other groups.
+	groups := [][]proto.Message{
+		{&pb3.Message{}, &pb3.Message{Key: []uint64{}, Data: []byte{}}},
+		{&pb2.MyMessage{}},
+		{&pb3.Message{Name: "a"}, toDynamic(t, &pb3.Message{Name: "a"})},
+		{&pb3.Message{Name: "b"}},
+		{&pb3.Message{Key: []uint64{1, 2}}},
+		{&pb3.Message{Key: []uint64{2, 1}}},
+		{&pb3.Message{Data: []byte("a")}},
+		{&pb3.Message{Children: []*pb3.Message{{Name: "a"}}}},
+		{&pb3.Message{Children: []*pb3.Message{{}, {Name: "a"}}}},
+		{
+			&pb3.Message{Terrain: map[string]*pb3.Nested{"a": {Bunny: "1"}, "b": {Cute: true}, "c": {}}},
+			toDynamic(t, &pb3.Message{Terrain: map[string]*pb3.Nested{"c": {}, "b": {Cute: true}, "a": {Bunny: "1"}}}),
+		},
+		{&pb3.Message{Terrain: map[string]*pb3.Nested{"a": {Bunny: "1"}, "b": {}, "c": {Cute: true}}}},
+		{&pb3.Message{Score: float32(math.NaN())}, &pb3.Message{Score: -float32(math.NaN())}},
+		{&pb3.Message{Score: float32(negZero)}},
+		{&pb2.MyMessage{Bigfloat: proto.Float64(0)}, &pb2.MyMessage{Bigfloat: proto.Float64(negZero)}},
+		{&pb2.MyMessage{Count: proto.Int32(0)}},
+		{&pb2.MyMessage{Name: proto.String("")}},
+		{&pb2.MyMessage{Inner: &pb2.InnerMessage{}}},
+		{&pb2.MyMessage{Somegroup: &pb2.MyMessage_SomeGroup{}}},
+		{&pb2.MyMessage{Count: proto.Int32(1)}},
+		{ext, toDynamic(t, ext)},
+		{&pb2.MessageWithMap{NameMapping: map[int32]string{-1: "x", 1: "y"}}},
+		{&pb2.MessageWithMap{NameMapping: map[int32]string{-1: "y", 1: "x"}}},
+		{&pb2.MessageWithMap{ByteMapping: map[bool][]byte{false: nil}}},
+		{&pb2.MessageWithMap{ByteMapping: map[bool][]byte{true: nil}}},
+		{withUnknown(&pb3.Message{}, u1), withUnknown(&pb3.Message{}, u2)},
+		{withUnknown(&pb3.Message{}, u3)},
+	}
+
+	type result struct {
+		group, index int
+		h64          uint64
+		h256         [32]byte
+	}
+	var results []result
+	for i, g := range groups {
+		for j, m := range g {
+			results = append(results, result{i, j, proto.Hash(m), proto.HashSHA256(m)})
+			if h := proto.Hash(m); h != results[len(results)-1].h64 {
+				t.Errorf("Hash(%v) is not stable: %x, %x", m, results[len(results)-1].h64, h)
+			}
+		}
+	}
+	for _, x := range results {
+		for _, y := range results {
+			mx, my := groups[x.group][x.index], groups[y.group][y.index]
+			name := fmt.Sprintf("(%T %v, %T %v)", mx, mx, my, my)
+			if equal := proto.Equal(mx, my); equal != (x.group == y.group) {
+				t.Fatalf("Equal%s = %v, but messages are in groups %d and %d", name, equal, x.group, y.group)
+			}
+			if (x.h64 == y.h64) != (x.group == y.group) {
+				t.Errorf("Hash%s: %x, %x", name, x.h64, y.h64)
+			}
+			if (x.h256 == y.h256) != (x.group == y.group) {
+				t.Errorf("HashSHA256%s: %x, %x", name, x.h256, y.h256)
+			}
+		}
+	}
+}
+
+func TestHashStable(t *testing.T) {
+	// The hash must not change across versions.
+	m := &pb2.MyMessage{
+		Count:     proto.Int32(42),
+		Name:      proto.String("name"),
+		Pet:       []string{"horsey", "bunny"},
+		Inner:     &pb2.InnerMessage{Host: proto.String("h"), Port: proto.Int32(-1)},
+		Bikeshed:  pb2.MyMessage_GREEN.Enum(),
+		Somegroup: &pb2.MyMessage_SomeGroup{GroupField: proto.Int32(7)},
+		Bigfloat:  proto.Float64(1.5),
+	}
+	if got, want := proto.Hash(m), uint64(0xed7e2c950673b8ae); got != want {
+		t.Errorf("Hash = %#x, want %#x", got, want)
+	}
+	if got, want := fmt.Sprintf("%x", proto.HashSHA256(m)), "f4e08b2612bd115ea744028a43df61c26eb28724cfb2106f2c92532dbbc3a15b"; got != want {
+		t.Errorf("HashSHA256 = %s, want %s", got, want)
+	}
+}
+
+func TestHashNil(t *testing.T) {
+	if got, want := proto.Hash((*pb3.Message)(nil)), proto.Hash(&pb3.Message{}); got != want {
+		t.Errorf("Hash(nil pointer) = %x, want %x", got, want)
+	}
+	if proto.Hash(nil) == proto.Hash(&pb3.Message{}) {
+		t.Errorf("Hash(nil) = Hash(empty message)")
+	}
+}
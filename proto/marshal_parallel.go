@@ -0,0 +1,189 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// minParallelListLen is the minimum length of a repeated message field
+// for its elements to be encoded in parallel.
+const minParallelListLen = 16
+
+// SetParallelism specifies the number of goroutines that Marshal and
+// EncodeMessage may use to encode the elements of large repeated message
+// fields of the top-level message concurrently.
+// A value of 0 or 1, the default, disables parallel encoding.
+//
+// The output of parallel encoding is byte-for-byte identical to that of
+// serial encoding with the same deterministic setting. It pays off for
+// messages with many large elements in repeated fields, such as batches of
+// records; for small messages, the coordination overhead dominates.
+func (b *Buffer) SetParallelism(n int) {
+	b.parallelism = n
+}
+
+// marshalAppendParallel is like marshalAppend, but encodes the elements of
+// large repeated message fields of m using up to parallelism goroutines.
+//
+// To produce the same output as serial encoding, it first serially encodes
+// a copy of m where each such field holds as many empty messages as the
+// original field has elements. The encoding of each empty element is just
+// a tag and a zero length, which are then replaced by the tag, length, and
+// encoding of the respective original element. This relies only on
+// the elements of a repeated field being encoded contiguously.
+func marshalAppendParallel(buf []byte, m Message, deterministic bool, parallelism int) ([]byte, error) {
+	if m == nil || parallelism <= 1 {
+		return marshalAppend(buf, m, deterministic)
+	}
+	mi := MessageV2(m)
+	mr := mi.ProtoReflect()
+	if !mr.IsValid() {
+		return marshalAppend(buf, m, deterministic)
+	}
+	var fds []protoreflect.FieldDescriptor
+	mr.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsList() && fd.Kind() == protoreflect.MessageKind && v.List().Len() >= minParallelListLen {
+			fds = append(fds, fd)
+		}
+		return true
+	})
+	if len(fds) == 0 {
+		return marshalAppend(buf, m, deterministic)
+	}
+
+	// Encode the skeleton of m with placeholders for the elements of fds.
+	skel := mr.Type().New()
+	mr.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		skel.Set(fd, v)
+		return true
+	})
+	skel.SetUnknown(mr.GetUnknown())
+	for _, fd := range fds {
+		list := skel.NewField(fd).List()
+		empty := list.NewElement()
+		for n := mr.Get(fd).List().Len(); n > 0; n-- {
+			list.Append(empty)
+		}
+		skel.Set(fd, protoreflect.ValueOfList(list))
+	}
+	opts := protoV2.MarshalOptions{
+		Deterministic: deterministic,
+		AllowPartial:  true,
+	}
+	skelBytes, err := opts.Marshal(skel.Interface())
+	if err != nil {
+		return buf, err
+	}
+
+	// Encode the elements of fds in chunks, from a queue shared by workers.
+	type chunk struct {
+		fd     protoreflect.FieldDescriptor
+		list   protoreflect.List
+		lo, hi int
+		b      []byte
+		err    error
+	}
+	var chunks []*chunk
+	for _, fd := range fds {
+		list := mr.Get(fd).List()
+		size := (list.Len() + 4*parallelism - 1) / (4 * parallelism)
+		for lo := 0; lo < list.Len(); lo += size {
+			hi := lo + size
+			if hi > list.Len() {
+				hi = list.Len()
+			}
+			chunks = append(chunks, &chunk{fd: fd, list: list, lo: lo, hi: hi})
+		}
+	}
+	queue := make(chan *chunk, len(chunks))
+	for _, c := range chunks {
+		queue <- c
+	}
+	close(queue)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism && i < len(chunks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Encode each element into a scratch buffer first, since its
+			// length must precede it and sizing a message is not free.
+			var scratch []byte
+			for c := range queue {
+				for j := c.lo; j < c.hi && c.err == nil; j++ {
+					e := c.list.Get(j).Message().Interface()
+					scratch, c.err = opts.MarshalAppend(scratch[:0], e)
+					if n := protowire.SizeTag(c.fd.Number()) + protowire.SizeBytes(len(scratch)); cap(c.b)-len(c.b) < n {
+						b := make([]byte, len(c.b), 2*cap(c.b)+n)
+						copy(b, c.b)
+						c.b = b
+					}
+					c.b = protowire.AppendTag(c.b, c.fd.Number(), protowire.BytesType)
+					c.b = protowire.AppendBytes(c.b, scratch)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Locate the placeholders in the skeleton.
+	type splice struct {
+		start, end int
+		fd         protoreflect.FieldDescriptor
+	}
+	var splices []splice
+	pending := make(map[protowire.Number]protoreflect.FieldDescriptor)
+	for _, fd := range fds {
+		pending[fd.Number()] = fd
+	}
+	for i := 0; i < len(skelBytes) && len(pending) > 0; {
+		num, typ, n := protowire.ConsumeField(skelBytes[i:])
+		if n < 0 {
+			return buf, protowire.ParseError(n)
+		}
+		fd, ok := pending[num]
+		if !ok || typ != protowire.BytesType {
+			i += n
+			continue
+		}
+		delete(pending, num)
+		start := i
+		for k := mr.Get(fd).List().Len(); k > 0; k-- {
+			i += protowire.SizeTag(num) + 1 // tag and zero length
+		}
+		splices = append(splices, splice{start, i, fd})
+	}
+	sort.Slice(splices, func(i, j int) bool { return splices[i].start < splices[j].start })
+
+	size := len(skelBytes)
+	for _, c := range chunks {
+		if c.err != nil {
+			return buf, c.err
+		}
+		size += len(c.b)
+	}
+	if cap(buf)-len(buf) < size {
+		nbuf := make([]byte, len(buf), len(buf)+size)
+		copy(nbuf, buf)
+		buf = nbuf
+	}
+	var last int
+	for _, s := range splices {
+		buf = append(buf, skelBytes[last:s.start]...)
+		for _, c := range chunks {
+			if c.fd == s.fd {
+				buf = append(buf, c.b...)
+			}
+		}
+		last = s.end
+	}
+	buf = append(buf, skelBytes[last:]...)
+	return buf, checkRequiredNotSet(mi)
+}
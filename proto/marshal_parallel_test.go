@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func parallelTestMessages() []proto.Message {
+	mm := &pb2.MyMessage{
+		Count:     proto.Int32(1),
+		Name:      proto.String("batch"),
+		Pet:       []string{"a", "b"},
+		Inner:     &pb2.InnerMessage{Host: proto.String("h")},
+		Somegroup: &pb2.MyMessage_SomeGroup{GroupField: proto.Int32(3)},
+	}
+	for i := 0; i < 100; i++ {
+		mm.Others = append(mm.Others, &pb2.OtherMessage{
+			Key:   proto.Int64(int64(i)),
+			Value: bytes.Repeat([]byte{byte(i)}, i),
+		})
+	}
+	mm.Others[7] = &pb2.OtherMessage{} // empty element
+	if err := proto.SetExtension(mm, pb2.E_Ext_More, &pb2.Ext{Data: proto.String("ext")}); err != nil {
+		panic(err)
+	}
+	proto.SetRawExtension(mm, 12345, []byte("\xc8\x83\x06\x01")) // unknown field
+
+	m3 := &pb3.Message{Name: "parent", Score: 1.5}
+	for i := 0; i < 50; i++ {
+		m3.Children = append(m3.Children, &pb3.Message{
+			Name:    fmt.Sprint("child", i),
+			Key:     []uint64{uint64(i)},
+			Terrain: map[string]*pb3.Nested{"a": {Bunny: "x"}, "b": {Bunny: "y"}, "c": {}},
+		})
+	}
+	m3.Terrain = map[string]*pb3.Nested{"meadow": {Cute: true}}
+
+	few := &pb3.Message{Name: "few", Children: []*pb3.Message{{Name: "only"}}}
+
+	rg := initGoTest(true)
+	for i := 0; i < 20; i++ {
+		rg.Repeatedgroup = append(rg.Repeatedgroup, initGoTest_RepeatedGroup())
+	}
+
+	return []proto.Message{mm, m3, few, rg, &pb3.Message{}}
+}
+
+func TestMarshalParallel(t *testing.T) {
+	for _, m := range parallelTestMessages() {
+		for _, deterministic := range []bool{false, true} {
+			want := proto.NewBuffer(nil)
+			want.SetDeterministic(deterministic)
+			if err := want.Marshal(m); err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			for _, parallelism := range []int{2, 3, 8} {
+				got := proto.NewBuffer([]byte("prefix"))
+				got.SetDeterministic(deterministic)
+				got.SetParallelism(parallelism)
+				if err := got.Marshal(m); err != nil {
+					t.Fatalf("Marshal with parallelism %d error: %v", parallelism, err)
+				}
+				if !bytes.HasPrefix(got.Bytes(), []byte("prefix")) {
+					t.Fatalf("Marshal with parallelism %d did not append to the buffer", parallelism)
+				}
+				// Without deterministic marshaling, only map ordering may differ.
+				gotBytes := got.Bytes()[len("prefix"):]
+				if deterministic && !bytes.Equal(gotBytes, want.Bytes()) {
+					t.Errorf("Marshal with parallelism %d mismatch:\ngot:  %x\nwant: %x", parallelism, gotBytes, want.Bytes())
+				}
+				if len(gotBytes) != len(want.Bytes()) {
+					t.Errorf("Marshal with parallelism %d produced %d bytes, want %d", parallelism, len(gotBytes), len(want.Bytes()))
+				}
+
+				got.Reset()
+				if err := got.EncodeMessage(m); err != nil {
+					t.Fatalf("EncodeMessage with parallelism %d error: %v", parallelism, err)
+				}
+				out := proto.Clone(m)
+				out.Reset()
+				if err := got.DecodeMessage(out); err != nil {
+					t.Fatalf("DecodeMessage error: %v", err)
+				}
+				if !proto.Equal(out, m) {
+					t.Errorf("EncodeMessage with parallelism %d round trip mismatch:\ngot:  %v\nwant: %v", parallelism, out, m)
+				}
+			}
+		}
+	}
+}
+
+func TestMarshalParallelRequiredNotSet(t *testing.T) {
+	m := &pb2.MyMessage{Count: proto.Int32(1)}
+	for i := 0; i < 32; i++ {
+		m.Others = append(m.Others, &pb2.OtherMessage{Inner: &pb2.InnerMessage{Host: proto.String("h")}})
+	}
+	m.Others[20].Inner = &pb2.InnerMessage{} // missing required host
+
+	want, wantErr := proto.Marshal(m)
+	buf := proto.NewBuffer(nil)
+	buf.SetParallelism(4)
+	gotErr := buf.Marshal(m)
+	if !isRequiredNotSetError(gotErr) {
+		t.Fatalf("Marshal with parallelism error = %v, want RequiredNotSetError", gotErr)
+	}
+	if !reflect.DeepEqual(gotErr, wantErr) {
+		t.Errorf("Marshal with parallelism error = %v, want %v", gotErr, wantErr)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Marshal with parallelism mismatch:\ngot:  %x\nwant: %x", buf.Bytes(), want)
+	}
+}
+
+func benchmarkMarshalParallel(b *testing.B, parallelism int) {
+	m := &pb3.Message{}
+	for i := 0; i < 10000; i++ {
+		m.Children = append(m.Children, &pb3.Message{
+			Name:     fmt.Sprint("child", i),
+			Key:      []uint64{1, 2, 3, 4, 5, 6, 7, 8},
+			Data:     bytes.Repeat([]byte{byte(i)}, 256),
+			Children: []*pb3.Message{{Name: "grandchild"}, {Name: "grandchild"}},
+		})
+	}
+	b.SetBytes(int64(proto.Size(m)))
+	b.ReportAllocs()
+	buf := proto.NewBuffer(nil)
+	buf.SetParallelism(parallelism)
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := buf.Marshal(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalSerial(b *testing.B)    { benchmarkMarshalParallel(b, 1) }
+func BenchmarkMarshalParallel4(b *testing.B) { benchmarkMarshalParallel(b, 4) }
@@ -0,0 +1,221 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MergeStrategy is the strategy for merging a field of a source message into
+// a destination message.
+type MergeStrategy int
+
+const (
+	// MergeDefault leaves the choice of strategy to the other options;
+	// by default, fields are merged with MergeCombine.
+	MergeDefault MergeStrategy = iota
+
+	// MergeCombine merges a field as Merge does: the elements of a repeated
+	// field are appended, the entries of a map field are set (replacing
+	// the values of existing keys), a message field is merged recursively,
+	// and a scalar field is overwritten.
+	MergeCombine
+
+	// MergeReplace clears the field in the destination message before
+	// merging, so that it holds a copy of the field of the source message.
+	MergeReplace
+
+	// MergeKeep merges a field only if it is not populated in the destination
+	// message, so that values already in the destination take precedence.
+	// A member of a oneof is merged only if no member of the oneof is
+	// populated in the destination.
+	MergeKeep
+
+	maxMergeStrategy = MergeKeep
+)
+
+// MergeOptions configures the merging of messages.
+// The zero value merges messages as Merge does.
+//
+// The options apply to each populated field of the source message, including
+// the fields of nested messages, which are merged with the same options.
+type MergeOptions struct {
+	// ReplaceRepeated specifies that repeated fields that are not maps
+	// are merged with MergeReplace rather than MergeCombine.
+	ReplaceRepeated bool
+
+	// ReplaceMaps specifies that map fields are merged with MergeReplace
+	// rather than MergeCombine.
+	ReplaceMaps bool
+
+	// Strategy, if non-nil, selects the strategy for merging the field fd.
+	// A result of MergeDefault defers to StrategyOption, ReplaceRepeated,
+	// and ReplaceMaps.
+	Strategy func(fd protoreflect.FieldDescriptor) MergeStrategy
+
+	// StrategyOption, if non-nil, is an extension of google.protobuf.FieldOptions
+	// of enum or integer kind, whose value in the options of a field selects
+	// the strategy for merging the field (e.g., 2 for MergeReplace).
+	// It is consulted for fields for which Strategy returns MergeDefault.
+	// For example, given:
+	//
+	//	extend google.protobuf.FieldOptions {
+	//		optional MergeStrategy merge = 50000;
+	//	}
+	//	message Config {
+	//		repeated string hosts = 1 [(merge) = REPLACE];
+	//	}
+	//
+	// the hosts field is replaced by merging with
+	// MergeOptions{StrategyOption: E_Merge}, where the enum value
+	// REPLACE has the number 2.
+	// Merge panics if the value of the option is not a valid MergeStrategy.
+	StrategyOption protoreflect.ExtensionType
+
+	// IsClear, if non-nil, reports whether the value v of the populated field fd
+	// of the source message is a sentinel which requests that the field be
+	// cleared in the destination message. This allows a source message to
+	// clear fields, such as scalar fields without presence, whose zero value
+	// would otherwise leave the destination unchanged.
+	IsClear func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool
+}
+
+// Merge merges src into dst, which must be messages of the same type,
+// according to the options. Unknown fields of src are appended to those
+// of dst.
+func (o MergeOptions) Merge(dst, src Message) {
+	dstMsg, srcMsg := MessageReflect(dst), MessageReflect(src)
+	if dstMsg.Descriptor() != srcMsg.Descriptor() {
+		if got, want := dstMsg.Descriptor().FullName(), srcMsg.Descriptor().FullName(); got != want {
+			panic(fmt.Sprintf("proto: descriptor mismatch: %v != %v", got, want))
+		}
+		panic("proto: descriptor mismatch")
+	}
+	o.mergeMessage(dstMsg, srcMsg)
+}
+
+func (o MergeOptions) mergeMessage(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if o.IsClear != nil && o.IsClear(fd, v) {
+			dst.Clear(fd)
+			return true
+		}
+		switch o.strategy(fd) {
+		case MergeReplace:
+			dst.Clear(fd)
+		case MergeKeep:
+			if od := fd.ContainingOneof(); od != nil && dst.WhichOneof(od) != nil || dst.Has(fd) {
+				return true
+			}
+		}
+		switch {
+		case fd.IsList():
+			o.mergeList(dst.Mutable(fd).List(), v.List(), fd)
+		case fd.IsMap():
+			o.mergeMap(dst.Mutable(fd).Map(), v.Map(), fd.MapValue())
+		case fd.Message() != nil:
+			o.mergeMessage(dst.Mutable(fd).Message(), v.Message())
+		case fd.Kind() == protoreflect.BytesKind:
+			dst.Set(fd, cloneBytes(v))
+		default:
+			dst.Set(fd, v)
+		}
+		return true
+	})
+	if len(src.GetUnknown()) > 0 {
+		dst.SetUnknown(append(dst.GetUnknown(), src.GetUnknown()...))
+	}
+}
+
+// mergeList appends copies of the elements of src to dst. Message elements
+// are merged into new messages, so that the options apply to their fields.
+func (o MergeOptions) mergeList(dst, src protoreflect.List, fd protoreflect.FieldDescriptor) {
+	for i := 0; i < src.Len(); i++ {
+		switch v := src.Get(i); {
+		case fd.Message() != nil:
+			nv := dst.NewElement()
+			o.mergeMessage(nv.Message(), v.Message())
+			dst.Append(nv)
+		case fd.Kind() == protoreflect.BytesKind:
+			dst.Append(cloneBytes(v))
+		default:
+			dst.Append(v)
+		}
+	}
+}
+
+// mergeMap sets copies of the entries of src in dst, where fd describes
+// the map values.
+func (o MergeOptions) mergeMap(dst, src protoreflect.Map, fd protoreflect.FieldDescriptor) {
+	src.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() != nil:
+			nv := dst.NewValue()
+			o.mergeMessage(nv.Message(), v.Message())
+			dst.Set(k, nv)
+		case fd.Kind() == protoreflect.BytesKind:
+			dst.Set(k, cloneBytes(v))
+		default:
+			dst.Set(k, v)
+		}
+		return true
+	})
+}
+
+func cloneBytes(v protoreflect.Value) protoreflect.Value {
+	return protoreflect.ValueOfBytes(append([]byte{}, v.Bytes()...))
+}
+
+// strategy returns the strategy for merging fd, which is never MergeDefault.
+func (o MergeOptions) strategy(fd protoreflect.FieldDescriptor) MergeStrategy {
+	if o.Strategy != nil {
+		if s := o.Strategy(fd); s != MergeDefault {
+			return s
+		}
+	}
+	if o.StrategyOption != nil {
+		if s := optionStrategy(fd, o.StrategyOption); s != MergeDefault {
+			return s
+		}
+	}
+	switch {
+	case fd.IsList() && o.ReplaceRepeated, fd.IsMap() && o.ReplaceMaps:
+		return MergeReplace
+	}
+	return MergeCombine
+}
+
+// optionStrategy returns the strategy selected by the value of the extension
+// xt in the options of fd, or MergeDefault if it is not set.
+func optionStrategy(fd protoreflect.FieldDescriptor, xt protoreflect.ExtensionType) MergeStrategy {
+	opts, ok := fd.Options().(protoV2.Message)
+	if !ok || opts == nil {
+		return MergeDefault
+	}
+	m, xd := opts.ProtoReflect(), xt.TypeDescriptor()
+	if m.Descriptor().FullName() != xd.ContainingMessage().FullName() || !m.Has(xd) {
+		return MergeDefault
+	}
+	var n int64
+	switch v := m.Get(xd); xd.Kind() {
+	case protoreflect.EnumKind:
+		n = int64(v.Enum())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n = v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n = int64(v.Uint())
+	default:
+		return MergeDefault
+	}
+	if n < int64(MergeDefault) || n > int64(maxMergeStrategy) {
+		panic(fmt.Sprintf("proto: invalid merge strategy %d in option %v of field %v", n, xd.FullName(), fd.FullName()))
+	}
+	return MergeStrategy(n)
+}
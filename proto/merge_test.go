@@ -0,0 +1,288 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protorand"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func TestMergeOptionsDefault(t *testing.T) {
+	// With no options, merging is equivalent to concatenating encodings.
+	g := protorand.New(1)
+	g.Extensions = true
+	for _, m := range []proto.Message{new(pb2.MyMessage), new(pb2.MessageWithMap), new(pb2.Oneof), new(pb3.Message)} {
+		md := proto.MessageReflect(m).Descriptor()
+		for i := 0; i < 50; i++ {
+			dst, src := g.Message(md), g.Message(md)
+			b1, err := proto.Marshal(dst)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			b2, err := proto.Marshal(src)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			want := proto.MessageV1(proto.MessageReflect(dst).New().Interface())
+			if err := proto.Unmarshal(append(b1, b2...), want); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			proto.MergeOptions{}.Merge(dst, src)
+			if !proto.Equal(dst, want) {
+				t.Fatalf("MergeOptions{}.Merge:\ngot:  %v\nwant: %v", dst, want)
+			}
+		}
+	}
+}
+
+func TestMergeOptionsReplace(t *testing.T) {
+	dst := &pb2.MyMessage{
+		Count:  proto.Int32(1),
+		Pet:    []string{"bunny", "kitty"},
+		Inner:  &pb2.InnerMessage{Host: proto.String("a"), Port: proto.Int32(1)},
+		Others: []*pb2.OtherMessage{{Key: proto.Int64(1)}},
+	}
+	src := &pb2.MyMessage{
+		Pet:   []string{"horsey"},
+		Inner: &pb2.InnerMessage{Host: proto.String("b"), Connected: proto.Bool(true)},
+	}
+	proto.MergeOptions{ReplaceRepeated: true}.Merge(dst, src)
+	want := &pb2.MyMessage{
+		Count:  proto.Int32(1),
+		Pet:    []string{"horsey"},
+		Inner:  &pb2.InnerMessage{Host: proto.String("b"), Port: proto.Int32(1), Connected: proto.Bool(true)},
+		Others: []*pb2.OtherMessage{{Key: proto.Int64(1)}},
+	}
+	if !proto.Equal(dst, want) {
+		t.Errorf("Merge with ReplaceRepeated:\ngot:  %v\nwant: %v", dst, want)
+	}
+	src.Pet[0] = "changed"
+	if dst.Pet[0] != "horsey" {
+		t.Errorf("Merge with ReplaceRepeated aliases the list of src")
+	}
+
+	mdst := &pb2.MessageWithMap{
+		NameMapping: map[int32]string{1: "a", 2: "b"},
+		StrToStr:    map[string]string{"k": "v"},
+	}
+	msrc := &pb2.MessageWithMap{
+		NameMapping: map[int32]string{2: "c"},
+		StrToStr:    map[string]string{"x": "y"},
+	}
+	opts := proto.MergeOptions{
+		ReplaceMaps: true,
+		Strategy: func(fd protoreflect.FieldDescriptor) proto.MergeStrategy {
+			if fd.Name() == "str_to_str" {
+				return proto.MergeCombine
+			}
+			return proto.MergeDefault
+		},
+	}
+	opts.Merge(mdst, msrc)
+	mwant := &pb2.MessageWithMap{
+		NameMapping: map[int32]string{2: "c"},
+		StrToStr:    map[string]string{"k": "v", "x": "y"},
+	}
+	if !proto.Equal(mdst, mwant) {
+		t.Errorf("Merge with ReplaceMaps:\ngot:  %v\nwant: %v", mdst, mwant)
+	}
+}
+
+func TestMergeOptionsStrategy(t *testing.T) {
+	dst := &pb3.Message{
+		Name:     "dst",
+		Nested:   &pb3.Nested{Bunny: "dst", Cute: true},
+		Key:      []uint64{1},
+		Children: []*pb3.Message{{Name: "dst"}},
+	}
+	src := &pb3.Message{
+		Name:        "src",
+		ResultCount: 2,
+		Nested:      &pb3.Nested{Bunny: "src"},
+		Key:         []uint64{2},
+		Children:    []*pb3.Message{{Name: "src", Key: []uint64{3}}},
+	}
+	opts := proto.MergeOptions{
+		Strategy: func(fd protoreflect.FieldDescriptor) proto.MergeStrategy {
+			switch fd.Name() {
+			case "name", "key":
+				return proto.MergeKeep
+			case "nested":
+				return proto.MergeReplace
+			}
+			return proto.MergeDefault
+		},
+	}
+	opts.Merge(dst, src)
+	want := &pb3.Message{
+		Name:        "dst",
+		ResultCount: 2,
+		Nested:      &pb3.Nested{Bunny: "src"},
+		Key:         []uint64{1},
+		Children:    []*pb3.Message{{Name: "dst"}, {Name: "src", Key: []uint64{3}}},
+	}
+	if !proto.Equal(dst, want) {
+		t.Errorf("Merge with Strategy:\ngot:  %v\nwant: %v", dst, want)
+	}
+}
+
+func TestMergeOptionsClear(t *testing.T) {
+	dst := &pb3.Message{
+		Name:        "dst",
+		ResultCount: 1,
+		Key:         []uint64{1},
+		Nested:      &pb3.Nested{Bunny: "dst", Cute: true},
+		StringMap:   map[string]string{"a": "b"},
+	}
+	src := &pb3.Message{
+		Name:        "-",
+		ResultCount: -1,
+		Key:         []uint64{2},
+		Nested:      &pb3.Nested{Bunny: "-"},
+		StringMap:   map[string]string{"-": ""},
+	}
+	opts := proto.MergeOptions{
+		IsClear: func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			switch {
+			case fd.IsMap():
+				return v.Map().Has(protoreflect.ValueOfString("-").MapKey())
+			case fd.IsList():
+				return false
+			case fd.Kind() == protoreflect.StringKind:
+				return v.String() == "-"
+			case fd.Kind() == protoreflect.Int64Kind:
+				return v.Int() == -1
+			}
+			return false
+		},
+	}
+	opts.Merge(dst, src)
+	want := &pb3.Message{
+		Key:    []uint64{1, 2},
+		Nested: &pb3.Nested{Cute: true},
+	}
+	if !proto.Equal(dst, want) {
+		t.Errorf("Merge with IsClear:\ngot:  %v\nwant: %v", dst, want)
+	}
+}
+
+func TestMergeOptionsKeepOneof(t *testing.T) {
+	opts := proto.MergeOptions{
+		Strategy: func(protoreflect.FieldDescriptor) proto.MergeStrategy { return proto.MergeKeep },
+	}
+	dst := &pb2.Communique{Union: &pb2.Communique_Number{Number: 5}}
+	opts.Merge(dst, &pb2.Communique{Union: &pb2.Communique_Name{Name: "x"}})
+	if want := (&pb2.Communique{Union: &pb2.Communique_Number{Number: 5}}); !proto.Equal(dst, want) {
+		t.Errorf("Merge with MergeKeep of another oneof member:\ngot:  %v\nwant: %v", dst, want)
+	}
+	dst = &pb2.Communique{}
+	opts.Merge(dst, &pb2.Communique{Union: &pb2.Communique_Name{Name: "x"}})
+	if want := (&pb2.Communique{Union: &pb2.Communique_Name{Name: "x"}}); !proto.Equal(dst, want) {
+		t.Errorf("Merge with MergeKeep into unset oneof:\ngot:  %v\nwant: %v", dst, want)
+	}
+}
+
+// mergeStrategyOption returns an extension of google.protobuf.FieldOptions
+// for selecting merge strategies.
+func mergeStrategyOption(t *testing.T) protoreflect.ExtensionType {
+	optFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("merge_option.proto"),
+		Package:    proto.String("merge"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("strategy"),
+			Number:   proto.Int32(50000),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+			Extendee: proto.String(".google.protobuf.FieldOptions"),
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dynamicpb.NewExtensionType(optFile.Extensions().Get(0))
+}
+
+// mergeConfigType returns the descriptor of a message with the repeated
+// string fields tags and hosts, where the option xt of hosts is strategy.
+func mergeConfigType(t *testing.T, xt protoreflect.ExtensionType, strategy int32) protoreflect.MessageDescriptor {
+	hostsOptions := &descriptorpb.FieldOptions{}
+	proto.MessageReflect(hostsOptions).Set(xt.TypeDescriptor(), protoreflect.ValueOfInt32(strategy))
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("merge.proto"),
+		Package: proto.String("merge"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Config"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:   proto.String("tags"),
+				Number: proto.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}, {
+				Name:    proto.String("hosts"),
+				Number:  proto.Int32(2),
+				Label:   descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:    descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Options: hostsOptions,
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Messages().Get(0)
+}
+
+func newMergeConfig(md protoreflect.MessageDescriptor, tags, hosts []string) proto.Message {
+	m := dynamicpb.NewMessage(md)
+	for _, s := range tags {
+		m.Mutable(md.Fields().ByName("tags")).List().Append(protoreflect.ValueOfString(s))
+	}
+	for _, s := range hosts {
+		m.Mutable(md.Fields().ByName("hosts")).List().Append(protoreflect.ValueOfString(s))
+	}
+	return proto.MessageV1(m)
+}
+
+func TestMergeOptionsStrategyOption(t *testing.T) {
+	xt := mergeStrategyOption(t)
+	md := mergeConfigType(t, xt, int32(proto.MergeReplace))
+	dst := newMergeConfig(md, []string{"a"}, []string{"h1", "h2"})
+	proto.MergeOptions{StrategyOption: xt}.Merge(dst, newMergeConfig(md, []string{"b"}, []string{"h3"}))
+	if want := newMergeConfig(md, []string{"a", "b"}, []string{"h3"}); !proto.Equal(dst, want) {
+		t.Errorf("Merge with StrategyOption:\ngot:  %v\nwant: %v", dst, want)
+	}
+
+	for _, strategy := range []int32{-1, 7} {
+		md := mergeConfigType(t, xt, strategy)
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Merge with option value %d did not panic", strategy)
+				}
+			}()
+			proto.MergeOptions{StrategyOption: xt}.Merge(newMergeConfig(md, nil, nil), newMergeConfig(md, nil, []string{"h"}))
+		}()
+	}
+}
+
+func TestMergeOptionsMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Merge of messages of different types did not panic")
+		}
+	}()
+	proto.MergeOptions{}.Merge(new(pb2.MyMessage), new(pb3.Message))
+}
@@ -13,6 +13,8 @@
 package proto
 
 import (
+	"strings"
+
 	protoV2 "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/runtime/protoiface"
@@ -105,13 +107,25 @@ type Merger interface {
 // RequiredNotSetError is an error type returned when
 // marshaling or unmarshaling a message with missing required fields.
 type RequiredNotSetError struct {
+	// Fields are the paths of all missing required fields, relative to the
+	// marshaled or unmarshaled message, such as "inner.host",
+	// "others[2].inner.host", or `terrain["north"].host`.
+	// Extension fields are referred to by their full name in parentheses.
+	Fields []string
+
 	err error
 }
 
 func (e *RequiredNotSetError) Error() string {
+	if len(e.Fields) > 1 {
+		return "proto: required fields not set: " + strings.Join(e.Fields, ", ")
+	}
 	if e.err != nil {
 		return e.err.Error()
 	}
+	if len(e.Fields) == 1 {
+		return "proto: required field " + e.Fields[0] + " not set"
+	}
 	return "proto: required field not set"
 }
 func (e *RequiredNotSetError) RequiredNotSet() bool {
@@ -120,11 +134,56 @@ func (e *RequiredNotSetError) RequiredNotSet() bool {
 
 func checkRequiredNotSet(m protoV2.Message) error {
 	if err := protoV2.CheckInitialized(m); err != nil {
-		return &RequiredNotSetError{err: err}
+		e := &RequiredNotSetError{err: err}
+		e.Fields = appendRequiredNotSet(e.Fields, m.ProtoReflect(), "")
+		return e
 	}
 	return nil
 }
 
+// appendRequiredNotSet appends the paths of the missing required fields
+// in m to paths, where prefix is the path of m itself.
+func appendRequiredNotSet(paths []string, m protoreflect.Message, prefix string) []string {
+	fds := m.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		path := prefix + fieldPathName(fd)
+		switch {
+		case fd.Cardinality() == protoreflect.Required && !m.Has(fd):
+			paths = append(paths, path)
+		case m.Has(fd):
+			paths = appendRequiredNotSetValue(paths, fd, m.Get(fd), path)
+		}
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsExtension() {
+			paths = appendRequiredNotSetValue(paths, fd, v, prefix+fieldPathName(fd))
+		}
+		return true
+	})
+	return paths
+}
+
+// appendRequiredNotSetValue appends the paths of the missing required fields
+// in the value v of the field fd at path.
+func appendRequiredNotSetValue(paths []string, fd protoreflect.FieldDescriptor, v protoreflect.Value, path string) []string {
+	switch {
+	case fd.IsList() && fd.Message() != nil:
+		list := v.List()
+		for i := 0; i < list.Len(); i++ {
+			paths = appendRequiredNotSet(paths, list.Get(i).Message(), path+indexPathSegment(i)+".")
+		}
+	case fd.IsMap() && fd.MapValue().Message() != nil:
+		// Sort the entries for a deterministic order of the paths.
+		for _, e := range sortedMapEntries(fd, v.Map()) {
+			paths = appendRequiredNotSet(paths, e.val.Message(), path+mapKeyPathSegment(e.key, fd.MapKey())+".")
+		}
+	case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+		paths = appendRequiredNotSet(paths, v.Message(), path+".")
+	}
+	return paths
+}
+
 // Clone returns a deep copy of src.
 func Clone(src Message) Message {
 	return MessageV1(protoV2.Clone(MessageV2(src)))
@@ -138,6 +197,7 @@ func Clone(src Message) Message {
 // list fields in dst. The entries of every map field in src is copied into
 // the corresponding map field in dst, possibly replacing existing entries.
 // The unknown fields of src are appended to the unknown fields of dst.
+// Use MergeOptions to merge with other strategies, such as replacing lists.
 func Merge(dst, src Message) {
 	protoV2.Merge(MessageV2(dst), MessageV2(src))
 }
@@ -205,6 +205,80 @@ func TestNumericPrimitives(t *testing.T) {
 	}
 }
 
+// Simple tests for tag encode/decode and field skipping.
+func TestTagPrimitives(t *testing.T) {
+	o := new(proto.Buffer)
+	o.EncodeTag(1, proto.WireVarint)
+	o.EncodeVarint(150)
+	o.EncodeTag(2, proto.WireFixed32)
+	o.EncodeFixed32(1)
+	o.EncodeTag(3, proto.WireFixed64)
+	o.EncodeFixed64(2)
+	o.EncodeTag(4, proto.WireBytes)
+	o.EncodeStringBytes("hello")
+	o.EncodeTag(5, proto.WireStartGroup)
+	o.EncodeTag(1, proto.WireVarint)
+	o.EncodeVarint(1)
+	o.EncodeTag(6, proto.WireStartGroup) // nested group
+	o.EncodeTag(6, proto.WireEndGroup)
+	o.EncodeTag(5, proto.WireEndGroup)
+	o.EncodeTag(536870911, proto.WireVarint)
+	o.EncodeVarint(7)
+
+	want := []struct {
+		num int32
+		typ int
+	}{
+		{1, proto.WireVarint},
+		{2, proto.WireFixed32},
+		{3, proto.WireFixed64},
+		{4, proto.WireBytes},
+		{5, proto.WireStartGroup},
+		{536870911, proto.WireVarint},
+	}
+	for _, w := range want {
+		num, typ, err := o.PeekTag()
+		if err != nil || num != w.num || typ != w.typ {
+			t.Fatalf("PeekTag() = (%v, %v, %v), want (%v, %v, nil)", num, typ, err, w.num, w.typ)
+		}
+		num, typ, err = o.DecodeTag()
+		if err != nil || num != w.num || typ != w.typ {
+			t.Fatalf("DecodeTag() = (%v, %v, %v), want (%v, %v, nil)", num, typ, err, w.num, w.typ)
+		}
+		if err := o.SkipField(typ); err != nil {
+			t.Fatalf("SkipField(%v) error: %v", typ, err)
+		}
+	}
+	if n := len(o.Unread()); n != 0 {
+		t.Fatalf("%d unread bytes after skipping all fields", n)
+	}
+	if _, _, err := o.PeekTag(); err == nil {
+		t.Errorf("PeekTag() on empty buffer succeeded, want error")
+	}
+
+	if err := o.EncodeTag(0, proto.WireVarint); err == nil {
+		t.Errorf("EncodeTag(0, WireVarint) succeeded, want error")
+	}
+	if err := o.EncodeTag(536870912, proto.WireVarint); err == nil {
+		t.Errorf("EncodeTag(536870912, WireVarint) succeeded, want error")
+	}
+	if err := o.EncodeTag(1, 6); err == nil {
+		t.Errorf("EncodeTag(1, 6) succeeded, want error")
+	}
+	if _, _, err := proto.NewBuffer([]byte{0x00}).DecodeTag(); err == nil {
+		t.Errorf("DecodeTag() of field number 0 succeeded, want error")
+	}
+	if err := proto.NewBuffer([]byte{0x05, 'a'}).SkipField(proto.WireBytes); err == nil {
+		t.Errorf("SkipField(WireBytes) of truncated bytes succeeded, want error")
+	}
+	if err := proto.NewBuffer([]byte{0x08, 0x01}).SkipField(proto.WireStartGroup); err == nil {
+		t.Errorf("SkipField(WireStartGroup) of unterminated group succeeded, want error")
+	}
+	if err := proto.NewBuffer([]byte{0x08}).SkipField(proto.WireEndGroup); err == nil {
+		t.Errorf("SkipField(WireEndGroup) succeeded, want error")
+	}
+}
+
 // fakeMarshaler is a simple struct implementing Marshaler and Message interfaces.
 type fakeMarshaler struct {
 	b   []byte
@@ -319,6 +393,101 @@ func TestBufferMarshalAllocs(t *testing.T) {
 	}
 }
 
+func TestBufferPool(t *testing.T) {
+	m1 := &pb2.MyMessage{Count: proto.Int32(1), Name: proto.String("first")}
+	m2 := &pb2.MyMessage{Count: proto.Int32(2), Name: proto.String("second message")}
+	want1, err := proto.Marshal(m1)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	b := proto.GetBuffer()
+	if err := b.Marshal(m1); err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	got1 := append([]byte(nil), b.Bytes()...)
+	b.SetAliasing(true)
+	b.SetDeterministic(true)
+	proto.PutBuffer(b)
+
+	// Reuse buffers from the pool; data copied before release must not change.
+	for i := 0; i < 10; i++ {
+		b := proto.GetBuffer()
+		if n := len(b.Bytes()); n != 0 {
+			t.Fatalf("GetBuffer returned a Buffer with %d bytes, want 0", n)
+		}
+
+		// Settings of released buffers must not leak into new ones.
+		in := append([]byte(nil), want1...)
+		b.SetBuf(in)
+		got := new(pb2.MyMessage)
+		if err := b.Unmarshal(got); err != nil {
+			t.Fatalf("Unmarshal error: %v", err)
+		}
+		for i := range in {
+			in[i] = 0
+		}
+		if !proto.Equal(got, m1) {
+			t.Fatalf("Unmarshal of a pooled Buffer aliases its input: got %v, want %v", got, m1)
+		}
+
+		b.Reset()
+		if err := b.Marshal(m2); err != nil {
+			t.Fatalf("Marshal error: %v", err)
+		}
+		proto.PutBuffer(b)
+	}
+	if !bytes.Equal(got1, want1) {
+		t.Errorf("bytes copied from a released Buffer = %x, want %x", got1, want1)
+	}
+}
+
+func TestBufferPoolConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m := &pb2.MyMessage{Count: proto.Int32(int32(g)), Name: proto.String(strings.Repeat("x", i))}
+				want, err := proto.Marshal(m)
+				if err != nil {
+					t.Errorf("Marshal error: %v", err)
+					return
+				}
+				b := proto.GetBuffer()
+				if err := b.Marshal(m); err != nil {
+					t.Errorf("Buffer.Marshal error: %v", err)
+				}
+				if !bytes.Equal(b.Bytes(), want) {
+					t.Errorf("pooled Buffer.Marshal = %x, want %x", b.Bytes(), want)
+				}
+				proto.PutBuffer(b)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestBufferPoolAllocs(t *testing.T) {
+	msg := &pb2.MyMessage{Count: proto.Int32(1), Name: proto.String("name")}
+	marshalAllocs := testing.AllocsPerRun(100, func() {
+		if _, err := proto.Marshal(msg); err != nil {
+			t.Errorf("Marshal err = %v", err)
+		}
+	})
+	poolAllocs := testing.AllocsPerRun(100, func() {
+		b := proto.GetBuffer()
+		if err := b.Marshal(msg); err != nil {
+			t.Errorf("Marshal err = %v", err)
+		}
+		proto.PutBuffer(b)
+	})
+	if poolAllocs >= marshalAllocs {
+		t.Errorf("%v allocs/op with pooled buffers, want fewer than %v allocs/op with Marshal", poolAllocs, marshalAllocs)
+	}
+}
+
 // Simple tests for bytes
 func TestBytesPrimitives(t *testing.T) {
 	bb := new(proto.Buffer)
@@ -1773,6 +1942,53 @@ func TestRequiredNotSetError(t *testing.T) {
 	}
 }
 
+func TestRequiredNotSetErrorFields(t *testing.T) {
+	tests := []struct {
+		in   proto.Message
+		want []string
+	}{{
+		in: &pb2.MyMessage{
+			Inner:          &pb2.InnerMessage{},
+			Others:         []*pb2.OtherMessage{{}, {Inner: &pb2.InnerMessage{}}},
+			WeMustGoDeeper: &pb2.RequiredInnerMessage{},
+			RepInner:       []*pb2.InnerMessage{{Host: proto.String("h")}, {}},
+		},
+		want: []string{
+			"count",
+			"inner.host",
+			"others[1].inner.host",
+			"we_must_go_deeper.leo_finally_won_an_oscar",
+			"rep_inner[1].host",
+		},
+	}, {
+		in: &pb2.MessageWithMap{
+			MsgMapping: map[int64]*pb2.FloatingPoint{10: {}, -1: {}, 2: {F: proto.Float64(1)}},
+		},
+		want: []string{"msg_mapping[-1].f", "msg_mapping[10].f"},
+	}, {
+		in:   &pb2.MyMessage{Inner: &pb2.InnerMessage{}, Count: proto.Int32(1)},
+		want: []string{"inner.host"},
+	}}
+	for _, tt := range tests {
+		_, err := proto.Marshal(tt.in)
+		rnse, ok := err.(*proto.RequiredNotSetError)
+		if !ok {
+			t.Errorf("Marshal(%v) error = %v, want *RequiredNotSetError", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(rnse.Fields, tt.want) {
+			t.Errorf("Marshal(%v) missing fields = %q, want %q", tt.in, rnse.Fields, tt.want)
+		}
+		if len(tt.want) > 1 {
+			for _, f := range tt.want {
+				if !strings.Contains(err.Error(), f) {
+					t.Errorf("Marshal(%v) error = %v, want mention of %v", tt.in, err, f)
+				}
+			}
+		}
+	}
+}
+
 func TestRequiredNotSetErrorWithBadWireTypes(t *testing.T) {
 	// Required field expects a varint, and properly found a varint.
 	if err := proto.Unmarshal([]byte{0x08, 0x00}, new(pb2.GoEnum)); err != nil {
@@ -0,0 +1,400 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Query selects field values in messages of a given type by their path,
+// such as "items[*].price", `labels["env"]`, or "(my.pkg.ext).field".
+//
+// A path is a sequence of fields separated by dots, where all but the last
+// refer to message fields or to repeated or map fields of messages.
+// Extension fields are referred to by their full name in parentheses,
+// and are resolved using the global registry.
+//
+// A repeated field may be followed by the index of an element in brackets
+// (e.g., "items[2]"), and a map field by the key of an entry in brackets
+// (e.g., `labels["env"]` for a string key, or "counts[-7]" for an integer
+// key). Either may be followed by "[*]" to select all elements or entries,
+// which is also what a repeated or map field without brackets selects.
+//
+// The paths reported by FieldValue.Path are valid queries
+// that select the visited value.
+type Query struct {
+	path  string
+	md    protoreflect.MessageDescriptor
+	steps []queryStep
+}
+
+type queryStep struct {
+	fd    protoreflect.FieldDescriptor
+	index int                 // index of a list element, or -1
+	key   protoreflect.MapKey // key of a map entry, if valid
+	all   bool                // whether all elements or entries are selected
+}
+
+// ParseQuery parses path as a query for messages of the type described by md.
+func ParseQuery(md protoreflect.MessageDescriptor, path string) (*Query, error) {
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("proto: invalid query %q: %s", path, fmt.Sprintf(format, args...))
+	}
+	q := &Query{path: path, md: md}
+	for s := path; ; {
+		step := queryStep{index: -1}
+		if strings.HasPrefix(s, "(") {
+			i := strings.IndexByte(s, ')')
+			if i < 0 {
+				return nil, errorf("missing closing parenthesis")
+			}
+			var err error
+			if step.fd, err = findExtensionField(md, protoreflect.FullName(s[1:i])); err != nil {
+				return nil, errorf("%v", err)
+			}
+			s = s[i+1:]
+		} else {
+			i := strings.IndexAny(s, ".[")
+			if i < 0 {
+				i = len(s)
+			}
+			name := protoreflect.Name(s[:i])
+			if step.fd = md.Fields().ByName(name); step.fd == nil {
+				return nil, errorf("message %v has no field %q", md.FullName(), name)
+			}
+			s = s[i:]
+		}
+
+		fd := step.fd
+		switch {
+		case strings.HasPrefix(s, "["):
+			arg, rest, ok := cutBracket(s)
+			if !ok {
+				return nil, errorf("missing closing bracket")
+			}
+			switch {
+			case !fd.IsList() && !fd.IsMap():
+				return nil, errorf("field %v is neither repeated nor a map", fd.FullName())
+			case arg == "*":
+				step.all = true
+			case fd.IsList():
+				n, err := strconv.Atoi(arg)
+				if err != nil || n < 0 {
+					return nil, errorf("invalid index %s for field %v", arg, fd.FullName())
+				}
+				step.index = n
+			default:
+				k, err := parseMapKey(fd.MapKey(), arg)
+				if err != nil {
+					return nil, errorf("invalid key %s for field %v", arg, fd.FullName())
+				}
+				step.key = k
+			}
+			s = rest
+		case fd.IsList() || fd.IsMap():
+			step.all = true
+		}
+		q.steps = append(q.steps, step)
+
+		if s == "" {
+			return q, nil
+		}
+		if s[0] != '.' {
+			return nil, errorf("unexpected %q after field %v", s, fd.FullName())
+		}
+		if fd.IsMap() {
+			md = fd.MapValue().Message()
+		} else {
+			md = fd.Message()
+		}
+		if md == nil {
+			return nil, errorf("field %v does not have message values", fd.FullName())
+		}
+		s = s[1:]
+	}
+}
+
+// cutBracket splits s, which starts with an opening bracket, into the text
+// within the brackets and the rest after the closing bracket.
+// Brackets within a quoted string are not considered.
+func cutBracket(s string) (arg, rest string, ok bool) {
+	i := 1
+	if len(s) > 1 && (s[1] == '"' || s[1] == '`') {
+		q, err := strconv.QuotedPrefix(s[1:])
+		if err != nil {
+			return "", "", false
+		}
+		i += len(q)
+	}
+	j := strings.IndexByte(s[i:], ']')
+	if j < 0 {
+		return "", "", false
+	}
+	return s[1 : i+j], s[i+j+1:], true
+}
+
+// parseMapKey parses s as a key of the kind of kd,
+// where a string key is a quoted Go string literal.
+func parseMapKey(kd protoreflect.FieldDescriptor, s string) (protoreflect.MapKey, error) {
+	var v protoreflect.Value
+	switch kd.Kind() {
+	case protoreflect.StringKind:
+		x, err := strconv.Unquote(s)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		v = protoreflect.ValueOfString(x)
+	case protoreflect.BoolKind:
+		switch s {
+		case "true":
+			v = protoreflect.ValueOfBool(true)
+		case "false":
+			v = protoreflect.ValueOfBool(false)
+		default:
+			return protoreflect.MapKey{}, fmt.Errorf("invalid bool %q", s)
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		x, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		v = protoreflect.ValueOfInt32(int32(x))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		x, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		v = protoreflect.ValueOfInt64(x)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		x, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		v = protoreflect.ValueOfUint32(uint32(x))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		x, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		v = protoreflect.ValueOfUint64(x)
+	default:
+		return protoreflect.MapKey{}, fmt.Errorf("invalid key kind %v", kd.Kind())
+	}
+	return v.MapKey(), nil
+}
+
+// String returns the path of the query.
+func (q *Query) String() string {
+	return q.path
+}
+
+// Get returns the values selected by the query in m, in the order in which
+// Walk visits them. Unpopulated fields have no value.
+func (q *Query) Get(m Message) ([]protoreflect.Value, error) {
+	var vs []protoreflect.Value
+	err := q.rangeValues(m, false, func(v *FieldValue) error {
+		vs = append(vs, v.Value)
+		return nil
+	})
+	return vs, err
+}
+
+// Set sets the values selected by the query in m to v, populating the
+// messages and map entries along the path as needed.
+// Only existing elements of repeated fields and entries of maps are selected
+// by "[*]", and it is an error for an index to be out of range. It is also
+// an error for the query to select no values, as when it selects all the
+// elements of an empty repeated field; Set never appends elements.
+//
+// Set panics if v is not a valid value for the selected field,
+// in the same way as protoreflect.Message.Set.
+func (q *Query) Set(m Message, v protoreflect.Value) error {
+	return q.setValues(m, func(fv *FieldValue) error {
+		fv.Set(v)
+		return nil
+	})
+}
+
+// SetString is like Set, but parses the value from s, as is convenient for
+// flags of command-line tools. Strings and bytes are taken verbatim,
+// enums are referred to by the name or number of a value, and messages
+// are written in the text format.
+func (q *Query) SetString(m Message, s string) error {
+	return q.setValues(m, func(fv *FieldValue) error {
+		v, err := parseQueryValue(fv, s)
+		if err != nil {
+			return fmt.Errorf("proto: query %q: invalid value %q for field %v: %v", q.path, s, fv.Field.FullName(), err)
+		}
+		fv.Set(v)
+		return nil
+	})
+}
+
+// setValues calls fn for each value selected by the query in m,
+// populating the messages and map entries along the path,
+// and reports an error if there is no such value.
+func (q *Query) setValues(m Message, fn func(*FieldValue) error) error {
+	var n int
+	err := q.rangeValues(m, true, func(fv *FieldValue) error {
+		n++
+		return fn(fv)
+	})
+	if err == nil && n == 0 {
+		err = fmt.Errorf("proto: query %q selects no values to set", q.path)
+	}
+	return err
+}
+
+// Clear clears the values selected by the query in m, by clearing singular
+// fields, removing elements of repeated fields, and deleting map entries.
+func (q *Query) Clear(m Message) error {
+	return q.rangeValues(m, false, func(fv *FieldValue) error {
+		fv.Clear()
+		return nil
+	})
+}
+
+// rangeValues calls fn for each value selected by the query in m.
+// If populate is set, messages and map entries along the path are populated.
+func (q *Query) rangeValues(m Message, populate bool, fn func(*FieldValue) error) error {
+	if m == nil {
+		return nil
+	}
+	mr := MessageReflect(m)
+	if got := mr.Descriptor().FullName(); got != q.md.FullName() {
+		return fmt.Errorf("proto: query %q for %v used with %v", q.path, q.md.FullName(), got)
+	}
+	if !mr.IsValid() {
+		if populate {
+			return fmt.Errorf("proto: query %q: cannot set field of nil %T", q.path, m)
+		}
+		return nil
+	}
+	return q.rangeStep(mr, 0, nil, populate, fn)
+}
+
+func (q *Query) rangeStep(m protoreflect.Message, i int, up *FieldValue, populate bool, fn func(*FieldValue) error) error {
+	s := q.steps[i]
+	last := i == len(q.steps)-1
+	if !populate && !m.Has(s.fd) {
+		return nil
+	}
+	fv := &FieldValue{Parent: m, Field: s.fd, Index: -1, up: up}
+	visit := func() error {
+		if last {
+			return fn(fv)
+		}
+		return q.rangeStep(fv.Value.Message(), i+1, fv, populate, fn)
+	}
+	switch {
+	case s.fd.IsList() && s.all:
+		list := m.Get(s.fd).List()
+		for j := 0; j < list.Len(); {
+			*fv = FieldValue{Parent: m, Field: s.fd, Index: j, Value: list.Get(j), up: up}
+			if err := visit(); err != nil {
+				return err
+			}
+			if !fv.cleared {
+				j++
+			}
+		}
+		return nil
+	case s.fd.IsList():
+		list := m.Get(s.fd).List()
+		if s.index >= list.Len() {
+			if populate {
+				return fmt.Errorf("proto: query %q: index %d out of range for field %v with %d elements", q.path, s.index, s.fd.FullName(), list.Len())
+			}
+			return nil
+		}
+		fv.Index, fv.Value = s.index, list.Get(s.index)
+	case s.fd.IsMap() && s.all:
+		for _, e := range sortedMapEntries(s.fd, m.Get(s.fd).Map()) {
+			*fv = FieldValue{Parent: m, Field: s.fd, Index: -1, MapKey: e.key, Value: e.val, up: up}
+			if err := visit(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case s.fd.IsMap():
+		fv.MapKey, fv.Value = s.key, m.Get(s.fd).Map().Get(s.key)
+		if !fv.Value.IsValid() {
+			switch {
+			case !populate:
+				return nil
+			case !last:
+				fv.Value = m.Mutable(s.fd).Map().Mutable(s.key)
+			}
+		}
+	case !last && populate:
+		fv.Value = m.Mutable(s.fd)
+	default:
+		fv.Value = m.Get(s.fd)
+	}
+	return visit()
+}
+
+// parseQueryValue parses s as a value of the field or element v.
+func parseQueryValue(v *FieldValue, s string) (protoreflect.Value, error) {
+	fd := v.Field
+	if v.MapKey.IsValid() {
+		fd = fd.MapValue()
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		x, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(x), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		x, err := strconv.ParseInt(s, 0, 32)
+		return protoreflect.ValueOfInt32(int32(x)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		x, err := strconv.ParseInt(s, 0, 64)
+		return protoreflect.ValueOfInt64(x), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		x, err := strconv.ParseUint(s, 0, 32)
+		return protoreflect.ValueOfUint32(uint32(x)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		x, err := strconv.ParseUint(s, 0, 64)
+		return protoreflect.ValueOfUint64(x), err
+	case protoreflect.FloatKind:
+		x, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(x)), err
+	case protoreflect.DoubleKind:
+		x, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(x), err
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(s)), nil
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		x, err := strconv.ParseInt(s, 0, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown value of enum %v", fd.Enum().FullName())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(x)), nil
+	default:
+		var m protoreflect.Message
+		switch {
+		case v.Index >= 0:
+			m = v.Parent.Mutable(v.Field).List().NewElement().Message()
+		case v.MapKey.IsValid():
+			m = v.Parent.Mutable(v.Field).Map().NewValue().Message()
+		default:
+			m = v.Parent.NewField(v.Field).Message()
+		}
+		if err := prototext.Unmarshal([]byte(s), m.Interface()); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(m), nil
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func mustParseQuery(t *testing.T, m proto.Message, path string) *proto.Query {
+	t.Helper()
+	q, err := proto.ParseQuery(proto.MessageReflect(m).Descriptor(), path)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q) error: %v", path, err)
+	}
+	return q
+}
+
+func TestQueryGet(t *testing.T) {
+	m2 := &pb2.MyMessage{
+		Count:    proto.Int32(42),
+		Inner:    &pb2.InnerMessage{Host: proto.String("h")},
+		Others:   []*pb2.OtherMessage{{Key: proto.Int64(1)}, {}, {Key: proto.Int64(3)}},
+		RepBytes: [][]byte{[]byte("a"), []byte("b")},
+	}
+	if err := proto.SetExtension(m2, pb2.E_Ext_More, &pb2.Ext{Data: proto.String("ext")}); err != nil {
+		t.Fatal(err)
+	}
+	m3 := &pb3.Message{
+		Terrain:  map[string]*pb3.Nested{"b": {Bunny: "y"}, "a": {Bunny: "x"}, `"]`: {Bunny: "z"}},
+		Children: []*pb3.Message{{Name: "c0"}, {Name: "c1", Key: []uint64{7, 8}}},
+	}
+
+	tests := []struct {
+		in   proto.Message
+		path string
+		want []interface{}
+	}{
+		{m2, "count", []interface{}{int32(42)}},
+		{m2, "name", nil},
+		{m2, "inner.host", []interface{}{"h"}},
+		{m2, "inner.port", nil},
+		{m2, "others[*].key", []interface{}{int64(1), int64(3)}},
+		{m2, "others.key", []interface{}{int64(1), int64(3)}},
+		{m2, "others[2].key", []interface{}{int64(3)}},
+		{m2, "others[5].key", nil},
+		{m2, "rep_bytes[1]", []interface{}{[]byte("b")}},
+		{m2, "(proto2_test.Ext.more).data", []interface{}{"ext"}},
+		{m2, "(proto2_test.Ext.text)", nil},
+		{m3, `terrain["a"].bunny`, []interface{}{"x"}},
+		{m3, "terrain[`a`].bunny", []interface{}{"x"}},
+		{m3, `terrain["\"]"].bunny`, []interface{}{"z"}},
+		{m3, `terrain["c"].bunny`, nil},
+		{m3, `terrain[*].bunny`, []interface{}{"z", "x", "y"}},
+		{m3, "children[*].name", []interface{}{"c0", "c1"}},
+		{m3, "children[1].key[*]", []interface{}{uint64(7), uint64(8)}},
+		{m3, "children.key", []interface{}{uint64(7), uint64(8)}},
+	}
+	for _, tt := range tests {
+		q := mustParseQuery(t, tt.in, tt.path)
+		vs, err := q.Get(tt.in)
+		if err != nil {
+			t.Errorf("Get(%q) error: %v", tt.path, err)
+			continue
+		}
+		var got []interface{}
+		for _, v := range vs {
+			got = append(got, v.Interface())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Get(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestQueryWalkPaths(t *testing.T) {
+	m := &pb2.MessageWithMap{
+		NameMapping: map[int32]string{-1: "neg", 2: "two"},
+		MsgMapping:  map[int64]*pb2.FloatingPoint{-5: {F: proto.Float64(1.5)}},
+		ByteMapping: map[bool][]byte{true: []byte("t")},
+		StrToStr:    map[string]string{"a.b[c]": "x"},
+	}
+	err := proto.Walk(m, func(v *proto.FieldValue) error {
+		vs, err := mustParseQuery(t, m, v.Path()).Get(m)
+		if err != nil {
+			t.Errorf("Get(%q) error: %v", v.Path(), err)
+		}
+		if len(vs) != 1 || !vs[0].Equal(v.Value) {
+			t.Errorf("Get(%q) = %v, want [%v]", v.Path(), vs, v.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQuerySet(t *testing.T) {
+	m := &pb3.Message{
+		Children: []*pb3.Message{{Name: "a"}, {Name: "b"}},
+	}
+	sets := []struct {
+		path string
+		v    protoreflect.Value
+	}{
+		{"name", protoreflect.ValueOfString("n")},
+		{"nested.bunny", protoreflect.ValueOfString("bugs")},
+		{`terrain["x"].cute`, protoreflect.ValueOfBool(true)},
+		{"children[*].score", protoreflect.ValueOfFloat32(7)},
+		{"children[1].nested.cute", protoreflect.ValueOfBool(true)},
+	}
+	for _, s := range sets {
+		if err := mustParseQuery(t, m, s.path).Set(m, s.v); err != nil {
+			t.Errorf("Set(%q) error: %v", s.path, err)
+		}
+	}
+	want := &pb3.Message{
+		Name:    "n",
+		Nested:  &pb3.Nested{Bunny: "bugs"},
+		Terrain: map[string]*pb3.Nested{"x": {Cute: true}},
+		Children: []*pb3.Message{
+			{Name: "a", Score: 7},
+			{Name: "b", Score: 7, Nested: &pb3.Nested{Cute: true}},
+		},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("Set mismatch:\ngot:  %v\nwant: %v", m, want)
+	}
+
+	m2 := &pb2.MyMessage{}
+	q := mustParseQuery(t, m2, "(proto2_test.Ext.more).data")
+	if err := q.Set(m2, protoreflect.ValueOfString("ext")); err != nil {
+		t.Fatalf("Set(%v) error: %v", q, err)
+	}
+	ext, err := proto.GetExtension(m2, pb2.E_Ext_More)
+	if err != nil {
+		t.Fatalf("GetExtension error: %v", err)
+	}
+	if got := ext.(*pb2.Ext).GetData(); got != "ext" {
+		t.Errorf("Set(%v): extension data = %q, want %q", q, got, "ext")
+	}
+}
+
+func TestQuerySetString(t *testing.T) {
+	m := &pb2.MyMessage{Others: []*pb2.OtherMessage{{}, {}}}
+	sets := []struct{ path, s string }{
+		{"count", "0x10"},
+		{"name", "some name"},
+		{"bikeshed", "GREEN"},
+		{"bigfloat", "-1.5e3"},
+		{"inner", `host: "h" port: 8`},
+		{"others[*].weight", "0.5"},
+		{"others[1].value", "raw"},
+	}
+	for _, s := range sets {
+		if err := mustParseQuery(t, m, s.path).SetString(m, s.s); err != nil {
+			t.Errorf("SetString(%q, %q) error: %v", s.path, s.s, err)
+		}
+	}
+	want := &pb2.MyMessage{
+		Count:    proto.Int32(16),
+		Name:     proto.String("some name"),
+		Bikeshed: pb2.MyMessage_GREEN.Enum(),
+		Bigfloat: proto.Float64(-1500),
+		Inner:    &pb2.InnerMessage{Host: proto.String("h"), Port: proto.Int32(8)},
+		Others: []*pb2.OtherMessage{
+			{Weight: proto.Float32(0.5)},
+			{Weight: proto.Float32(0.5), Value: []byte("raw")},
+		},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("SetString mismatch:\ngot:  %v\nwant: %v", m, want)
+	}
+
+	m3 := &pb3.Message{}
+	q := mustParseQuery(t, m3, `terrain["k"]`)
+	if err := q.SetString(m3, "bunny: 'b'"); err != nil {
+		t.Fatalf("SetString(%v) error: %v", q, err)
+	}
+	if got := m3.GetTerrain()["k"].GetBunny(); got != "b" {
+		t.Errorf("SetString(%v): bunny = %q, want %q", q, got, "b")
+	}
+
+	for _, s := range []struct{ path, s string }{
+		{"count", "1.5"},
+		{"bikeshed", "PURPLE"},
+		{"inner", "unknown: 1"},
+	} {
+		if err := mustParseQuery(t, m, s.path).SetString(m, s.s); err == nil {
+			t.Errorf("SetString(%q, %q) succeeded, want error", s.path, s.s)
+		}
+	}
+}
+
+func TestQueryClear(t *testing.T) {
+	m := &pb3.Message{
+		Name:     "n",
+		Key:      []uint64{1, 2, 3},
+		Nested:   &pb3.Nested{Bunny: "b", Cute: true},
+		Terrain:  map[string]*pb3.Nested{"a": {}, "b": {}},
+		Children: []*pb3.Message{{Name: "a", Key: []uint64{1}}, {Name: "b"}},
+	}
+	for _, path := range []string{
+		"name",
+		"key[1]",
+		"nested.cute",
+		`terrain["a"]`,
+		`terrain["missing"]`,
+		"children[*].name",
+		"children[0].key",
+		"children[5]",
+		"proto2_field.n",
+	} {
+		if err := mustParseQuery(t, m, path).Clear(m); err != nil {
+			t.Errorf("Clear(%q) error: %v", path, err)
+		}
+	}
+	want := &pb3.Message{
+		Key:      []uint64{1, 3},
+		Nested:   &pb3.Nested{Bunny: "b"},
+		Terrain:  map[string]*pb3.Nested{"b": {}},
+		Children: []*pb3.Message{{}, {}},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("Clear mismatch:\ngot:  %v\nwant: %v", m, want)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	md := proto.MessageReflect(&pb2.MyMessage{}).Descriptor()
+	md3 := proto.MessageReflect(&pb3.Message{}).Descriptor()
+	for _, tt := range []struct {
+		md   protoreflect.MessageDescriptor
+		path string
+		want string
+	}{
+		{md, "", `has no field ""`},
+		{md, "nope", `has no field "nope"`},
+		{md, "inner.", `has no field ""`},
+		{md, "inner..host", `has no field ""`},
+		{md, "count.x", "does not have message values"},
+		{md, "count[0]", "neither repeated nor a map"},
+		{md, "pet[-1]", "invalid index -1"},
+		{md, "pet[x]", "invalid index x"},
+		{md, "pet[0", "missing closing bracket"},
+		{md, "pet[0]x", `unexpected "x"`},
+		{md, "(proto2_test.Ext.more", "missing closing parenthesis"},
+		{md, "(proto2_test.nope)", "unknown extension"},
+		{md3, "(proto2_test.Ext.more)", "does not extend"},
+		{md3, "terrain[a]", "invalid key a"},
+		{md3, `terrain["a]`, "missing closing bracket"},
+	} {
+		_, err := proto.ParseQuery(tt.md, tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseQuery(%q) error = %v, want error containing %q", tt.path, err, tt.want)
+		}
+	}
+
+	m := &pb3.Message{Children: []*pb3.Message{{}}}
+	if err := mustParseQuery(t, m, "children[1].name").Set(m, protoreflect.ValueOfString("x")); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("Set with index out of range: error = %v, want out of range", err)
+	}
+	if _, err := mustParseQuery(t, m, "name").Get(&pb2.MyMessage{}); err == nil {
+		t.Errorf("Get with message of another type succeeded, want error")
+	}
+	for _, path := range []string{"key", "key[*]", "children[*].key", "terrain[*].cute"} {
+		if err := mustParseQuery(t, m, path).Set(m, protoreflect.ValueOfUint64(1)); err == nil || !strings.Contains(err.Error(), "no values") {
+			t.Errorf("Set(%q) with no elements: error = %v, want no values", path, err)
+		}
+	}
+	if err := mustParseQuery(t, m, "children[0].children").SetString(m, "name: 'x'"); err == nil {
+		t.Errorf("SetString of unpopulated repeated field succeeded, want error")
+	}
+	if err := mustParseQuery(t, m, "name").Set((*pb3.Message)(nil), protoreflect.ValueOfString("x")); err == nil {
+		t.Errorf("Set with nil message succeeded, want error")
+	}
+	if vs, err := mustParseQuery(t, m, "name").Get((*pb3.Message)(nil)); err != nil || len(vs) > 0 {
+		t.Errorf("Get with nil message = %v, %v, want no values", vs, err)
+	}
+}
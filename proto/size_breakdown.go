@@ -0,0 +1,202 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// SizeNode is the contribution of a field to the size of the wire-format
+// encoding of a message, as reported by SizeBreakdown.
+type SizeNode struct {
+	// Name is the name of the field. Extension fields are referred to by
+	// their full name in parentheses, and unknown fields by their number
+	// (e.g., "unknown field 100"). The name of the root node is the full
+	// name of the message type.
+	Name string
+
+	// Field describes the field. It is nil for the root node
+	// and for unknown fields.
+	Field protoreflect.FieldDescriptor
+
+	// Size is the number of bytes taken by all values of the field,
+	// including their tags and length prefixes.
+	Size int
+
+	// Count is the number of values of the field: the number of elements
+	// of a repeated field, or the number of entries of a map field.
+	Count int
+
+	// Fields are the fields of the message values of the field, in order of
+	// decreasing Size. The nodes of each field account for all values:
+	// for example, the "key" field of the elements of a repeated message
+	// field "items" has a single node, whose Size is the total for all items.
+	// The fields of a map field are the "key" and "value" of its entries.
+	Fields []*SizeNode
+}
+
+// SizeBreakdown reports how the fields of m contribute to the size of its
+// wire-format encoding, which is the Size of the root node.
+//
+// Extension fields of nested messages are identified using the global
+// registry; those not found there are reported as unknown fields.
+func SizeBreakdown(m Message) (*SizeNode, error) {
+	if m == nil {
+		return nil, ErrNil
+	}
+	mi := MessageV2(m)
+	md := mi.ProtoReflect().Descriptor()
+	b, err := protoV2.MarshalOptions{AllowPartial: true}.Marshal(mi)
+	if err != nil {
+		return nil, err
+	}
+	root := &SizeNode{Name: string(md.FullName()), Size: len(b), Count: 1}
+	sb := sizeBuilder{children: make(map[*SizeNode]map[sizeKey]*SizeNode)}
+	if err := sb.addFields(root, b, md); err != nil {
+		return nil, err
+	}
+	sortSizeNodes(root)
+	return root, nil
+}
+
+type sizeKey struct {
+	num   protowire.Number
+	known bool
+}
+
+type sizeBuilder struct {
+	children map[*SizeNode]map[sizeKey]*SizeNode
+}
+
+// addFields adds the fields of the encoded message b of the type described
+// by md to the fields of n.
+func (sb *sizeBuilder) addFields(n *SizeNode, b []byte, md protoreflect.MessageDescriptor) error {
+	for len(b) > 0 {
+		num, wtyp, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return protowire.ParseError(tagLen)
+		}
+		fieldLen := protowire.ConsumeFieldValue(num, wtyp, b[tagLen:])
+		if fieldLen < 0 {
+			return protowire.ParseError(fieldLen)
+		}
+		v := b[tagLen : tagLen+fieldLen]
+		fieldLen += tagLen
+		b = b[fieldLen:]
+
+		fd := md.Fields().ByNumber(num)
+		if fd == nil && md.ExtensionRanges().Has(num) {
+			if xt, err := protoregistry.GlobalTypes.FindExtensionByNumber(md.FullName(), num); err == nil {
+				fd = xt.TypeDescriptor()
+			}
+		}
+		packed := fd != nil && isPackable(fd) && wtyp == protowire.BytesType
+		if fd != nil && wtyp != wireTypeOf(fd) && !packed {
+			fd = nil // a value with the wrong wire type is an unknown field
+		}
+		c := sb.child(n, fd, num)
+		c.Size += fieldLen
+		switch {
+		case fd == nil:
+			c.Count++
+		case packed:
+			v, _ = protowire.ConsumeBytes(v)
+			c.Count += countPacked(v, fd)
+		case fd.Message() != nil:
+			c.Count++
+			if wtyp == protowire.BytesType {
+				v, _ = protowire.ConsumeBytes(v)
+			} else {
+				v, _ = protowire.ConsumeGroup(num, v)
+			}
+			if err := sb.addFields(c, v, fd.Message()); err != nil {
+				return err
+			}
+		default:
+			c.Count++
+		}
+	}
+	return nil
+}
+
+// child returns the node of the field fd with number num in n,
+// where fd is nil for an unknown field.
+func (sb *sizeBuilder) child(n *SizeNode, fd protoreflect.FieldDescriptor, num protowire.Number) *SizeNode {
+	k := sizeKey{num, fd != nil}
+	if c := sb.children[n][k]; c != nil {
+		return c
+	}
+	c := &SizeNode{Field: fd}
+	switch {
+	case fd == nil:
+		c.Name = fmt.Sprintf("unknown field %d", num)
+	case fd.IsExtension():
+		c.Name = "(" + string(fd.FullName()) + ")"
+	default:
+		c.Name = string(fd.Name())
+	}
+	if sb.children[n] == nil {
+		sb.children[n] = make(map[sizeKey]*SizeNode)
+	}
+	sb.children[n][k] = c
+	n.Fields = append(n.Fields, c)
+	return c
+}
+
+// countPacked returns the number of values of fd in the packed encoding b.
+func countPacked(b []byte, fd protoreflect.FieldDescriptor) int {
+	switch wireTypeOf(fd) {
+	case protowire.Fixed32Type:
+		return len(b) / 4
+	case protowire.Fixed64Type:
+		return len(b) / 8
+	default:
+		var n int
+		for _, c := range b {
+			if c < 0x80 {
+				n++
+			}
+		}
+		return n
+	}
+}
+
+func sortSizeNodes(n *SizeNode) {
+	sort.SliceStable(n.Fields, func(i, j int) bool {
+		return n.Fields[i].Size > n.Fields[j].Size
+	})
+	for _, c := range n.Fields {
+		sortSizeNodes(c)
+	}
+}
+
+// String returns a text report of the size breakdown, with a line for
+// each node that shows its size in bytes, its share of the size of the
+// root, and its number of values. The fields of a node are indented below
+// it, in order of decreasing size.
+func (n *SizeNode) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%10s %7s %7s  %s\n", "BYTES", "SHARE", "COUNT", "FIELD")
+	n.writeReport(&sb, n.Size, 0)
+	return sb.String()
+}
+
+func (n *SizeNode) writeReport(sb *strings.Builder, total, depth int) {
+	share := 100.0
+	if total > 0 {
+		share = 100 * float64(n.Size) / float64(total)
+	}
+	fmt.Fprintf(sb, "%10d %6.1f%% %7d  %s%s\n", n.Size, share, n.Count, strings.Repeat("  ", depth), n.Name)
+	for _, c := range n.Fields {
+		c.writeReport(sb, total, depth+1)
+	}
+}
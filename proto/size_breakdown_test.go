@@ -0,0 +1,146 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/testing/protopack"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+// sizeSummary is a SizeNode without its field descriptor,
+// which is omitted for brevity.
+type sizeSummary struct {
+	name        string
+	size, count int
+	fields      []sizeSummary
+}
+
+func summarizeSize(n *proto.SizeNode) sizeSummary {
+	s := sizeSummary{name: n.Name, size: n.Size, count: n.Count}
+	for _, c := range n.Fields {
+		s.fields = append(s.fields, summarizeSize(c))
+	}
+	return s
+}
+
+func TestSizeBreakdown(t *testing.T) {
+	m := &pb2.MyMessage{
+		Count:  proto.Int32(1),                                                          // 2 bytes
+		Name:   proto.String("0123456789"),                                              // 12 bytes
+		Others: []*pb2.OtherMessage{{Key: proto.Int64(1)}, {Value: []byte("abcdefgh")}}, // 4 + 12 bytes
+	}
+	if err := proto.SetExtension(m, pb2.E_Ext_Text, proto.String("txt")); err != nil { // 3 + 3 bytes
+		t.Fatal(err)
+	}
+	proto.MessageReflect(m).SetUnknown(protopack.Message{
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(1), // 3 bytes
+		protopack.Tag{200, protopack.VarintType}, protopack.Varint(2), // 3 bytes
+	}.Marshal())
+
+	n, err := proto.SizeBreakdown(m)
+	if err != nil {
+		t.Fatalf("SizeBreakdown error: %v", err)
+	}
+	want := sizeSummary{"proto2_test.MyMessage", 42, 1, []sizeSummary{
+		{"others", 16, 2, []sizeSummary{
+			{"value", 10, 1, nil},
+			{"key", 2, 1, nil},
+		}},
+		{"name", 12, 1, nil},
+		{"(proto2_test.Ext.text)", 6, 1, nil},
+		{"unknown field 200", 6, 2, nil},
+		{"count", 2, 1, nil},
+	}}
+	if got := summarizeSize(n); !sizeSummaryEqual(got, want) {
+		t.Errorf("SizeBreakdown:\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if n.Size != proto.Size(m) {
+		t.Errorf("SizeBreakdown size = %d, want Size %d", n.Size, proto.Size(m))
+	}
+	if n.Fields[0].Field.Name() != "others" || n.Fields[3].Field != nil {
+		t.Errorf("SizeBreakdown reported wrong field descriptors")
+	}
+
+	report := n.String()
+	wantLines := []string{
+		"     BYTES   SHARE   COUNT  FIELD",
+		"        42  100.0%       1  proto2_test.MyMessage",
+		"        16   38.1%       2    others",
+		"        10   23.8%       1      value",
+		"         2    4.8%       1      key",
+		"        12   28.6%       1    name",
+	}
+	if !strings.HasPrefix(report, strings.Join(wantLines, "\n")+"\n") {
+		t.Errorf("SizeBreakdown report:\n%s\nwant prefix:\n%s", report, strings.Join(wantLines, "\n"))
+	}
+}
+
+func TestSizeBreakdownRepeated(t *testing.T) {
+	m := &pb3.Message{
+		Key:     []uint64{1, 300, 70000},                                      // packed: 2 + 1 + 2 + 3 bytes
+		Terrain: map[string]*pb3.Nested{"a": {Bunny: "b"}, "c": {Cute: true}}, // 2 entries of 10 and 9 bytes
+		Children: []*pb3.Message{
+			{Key: []uint64{1}},
+			{Nested: &pb3.Nested{Bunny: "xyz"}},
+		},
+	}
+	n, err := proto.SizeBreakdown(m)
+	if err != nil {
+		t.Fatalf("SizeBreakdown error: %v", err)
+	}
+	want := sizeSummary{"proto3_test.Message", 43, 1, []sizeSummary{
+		{"terrain", 19, 2, []sizeSummary{
+			{"value", 9, 2, []sizeSummary{
+				{"bunny", 3, 1, nil},
+				{"cute", 2, 1, nil},
+			}},
+			{"key", 6, 2, nil},
+		}},
+		{"children", 16, 2, []sizeSummary{
+			{"nested", 7, 1, []sizeSummary{
+				{"bunny", 5, 1, nil},
+			}},
+			{"key", 3, 1, nil},
+		}},
+		{"key", 8, 3, nil},
+	}}
+	if got := summarizeSize(n); !sizeSummaryEqual(got, want) {
+		t.Errorf("SizeBreakdown:\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if n.Size != proto.Size(m) {
+		t.Errorf("SizeBreakdown size = %d, want Size %d", n.Size, proto.Size(m))
+	}
+}
+
+func TestSizeBreakdownEmpty(t *testing.T) {
+	n, err := proto.SizeBreakdown(&pb3.Message{})
+	if err != nil {
+		t.Fatalf("SizeBreakdown error: %v", err)
+	}
+	if n.Size != 0 || len(n.Fields) != 0 {
+		t.Errorf("SizeBreakdown(empty) = %+v, want no fields", n)
+	}
+	if _, err := proto.SizeBreakdown(nil); err != proto.ErrNil {
+		t.Errorf("SizeBreakdown(nil) error = %v, want ErrNil", err)
+	}
+}
+
+func sizeSummaryEqual(x, y sizeSummary) bool {
+	if x.name != y.name || x.size != y.size || x.count != y.count || len(x.fields) != len(y.fields) {
+		return false
+	}
+	for i := range x.fields {
+		if !sizeSummaryEqual(x.fields[i], y.fields[i]) {
+			return false
+		}
+	}
+	return true
+}
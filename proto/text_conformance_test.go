@@ -0,0 +1,226 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+// textConformanceTests is a corpus of text format inputs for which
+// UnmarshalText must agree with the prototext package:
+// either both reject the input, or both accept it with equal results.
+var textConformanceTests = []struct {
+	in string
+	m  proto.Message
+}{
+	// Floating-point literals.
+	{in: `score: 1.5`, m: new(pb3.Message)},
+	{in: `score: 1.5f`, m: new(pb3.Message)},
+	{in: `score: 1.5F`, m: new(pb3.Message)},
+	{in: `score: 10f`, m: new(pb3.Message)},
+	{in: `score: -10f`, m: new(pb3.Message)},
+	{in: `score: 1E3f`, m: new(pb3.Message)},
+	{in: `score: 1e3`, m: new(pb3.Message)},
+	{in: `score: .5`, m: new(pb3.Message)},
+	{in: `score: 5.`, m: new(pb3.Message)},
+	{in: `score: 010`, m: new(pb3.Message)},
+	{in: `score: 0x10`, m: new(pb3.Message)},
+	{in: `score: 0x1p3`, m: new(pb3.Message)},
+	{in: `score: 1_0`, m: new(pb3.Message)},
+	{in: `score: - 1.5`, m: new(pb3.Message)},
+	{in: "score: -\n# comment\n1.5", m: new(pb3.Message)},
+	{in: `score: inf`, m: new(pb3.Message)},
+	{in: `score: -inf`, m: new(pb3.Message)},
+	{in: `score: Inf`, m: new(pb3.Message)},
+	{in: `score: -Inf`, m: new(pb3.Message)},
+	{in: `score: INF`, m: new(pb3.Message)},
+	{in: `score: infinity`, m: new(pb3.Message)},
+	{in: `score: -infinity`, m: new(pb3.Message)},
+	{in: `score: Infinity`, m: new(pb3.Message)},
+	{in: `score: nan`, m: new(pb3.Message)},
+	{in: `score: NaN`, m: new(pb3.Message)},
+	{in: `score: -nan`, m: new(pb3.Message)},
+	{in: `score: inff`, m: new(pb3.Message)},
+	{in: `score: -Inff`, m: new(pb3.Message)},
+	{in: `score: nanf`, m: new(pb3.Message)},
+	{in: `f: -0.0`, m: new(pb2.FloatingPoint)},
+
+	// Integer literals.
+	{in: `result_count: 0x10`, m: new(pb3.Message)},
+	{in: `result_count: 0X1F`, m: new(pb3.Message)},
+	{in: `result_count: 010`, m: new(pb3.Message)},
+	{in: `result_count: -0x10`, m: new(pb3.Message)},
+	{in: `result_count: - 5`, m: new(pb3.Message)},
+	{in: `result_count: 0b11`, m: new(pb3.Message)},
+	{in: `result_count: 0o7`, m: new(pb3.Message)},
+	{in: `result_count: 1_000`, m: new(pb3.Message)},
+	{in: `result_count: 1.0`, m: new(pb3.Message)},
+	{in: `result_count: 9223372036854775808`, m: new(pb3.Message)},
+	{in: `height_in_cm: -0`, m: new(pb3.Message)},
+	{in: `height_in_cm: -1`, m: new(pb3.Message)},
+	{in: `height_in_cm: 4294967296`, m: new(pb3.Message)},
+	{in: `short_key: 2147483648`, m: new(pb3.Message)},
+	{in: `short_key: -2147483648`, m: new(pb3.Message)},
+	{in: `key: -1`, m: new(pb3.Message)},
+
+	// Boolean literals.
+	{in: `true_scotsman: true`, m: new(pb3.Message)},
+	{in: `true_scotsman: True`, m: new(pb3.Message)},
+	{in: `true_scotsman: t`, m: new(pb3.Message)},
+	{in: `true_scotsman: False`, m: new(pb3.Message)},
+	{in: `true_scotsman: 1`, m: new(pb3.Message)},
+	{in: `true_scotsman: 0x1`, m: new(pb3.Message)},
+	{in: `true_scotsman: TRUE`, m: new(pb3.Message)},
+	{in: `true_scotsman: 2`, m: new(pb3.Message)},
+
+	// Enum literals.
+	{in: `hilarity: PUNS`, m: new(pb3.Message)},
+	{in: `hilarity: 1`, m: new(pb3.Message)},
+	{in: `hilarity: -1`, m: new(pb3.Message)},
+	{in: `hilarity: 99`, m: new(pb3.Message)},
+	{in: `hilarity: puns`, m: new(pb3.Message)},
+
+	// String literals.
+	{in: `name: "a" "b"`, m: new(pb3.Message)},
+	{in: "name: \"a\"\n'b'\n  \"c\"", m: new(pb3.Message)},
+	{in: `name: 'a"b'`, m: new(pb3.Message)},
+	{in: `name: "\0"`, m: new(pb3.Message)},
+	{in: `name: "\12"`, m: new(pb3.Message)},
+	{in: `name: "\1234"`, m: new(pb3.Message)},
+	{in: `name: "\x4"`, m: new(pb3.Message)},
+	{in: `name: "\x41"`, m: new(pb3.Message)},
+	{in: `name: "\x414"`, m: new(pb3.Message)},
+	{in: `name: "\xg"`, m: new(pb3.Message)},
+	{in: `name: "é"`, m: new(pb3.Message)},
+	{in: `name: "\U0001F600"`, m: new(pb3.Message)},
+	{in: `name: "😀"`, m: new(pb3.Message)},
+	{in: `name: "\uD83D"`, m: new(pb3.Message)},
+	{in: `name: "\uDE00\uD83D"`, m: new(pb3.Message)},
+	{in: `name: "\?\'\"\\\a\b\f\n\r\t\v"`, m: new(pb3.Message)},
+	{in: `name: "\z"`, m: new(pb3.Message)},
+	{in: "name: \"a\nb\"", m: new(pb3.Message)},
+	{in: `data: "\377\x00"`, m: new(pb3.Message)},
+
+	// Lists.
+	{in: `key: []`, m: new(pb3.Message)},
+	{in: `key: [1, 2]`, m: new(pb3.Message)},
+	{in: `key [1]`, m: new(pb3.Message)},
+	{in: `key: [1 2]`, m: new(pb3.Message)},
+	{in: `key: [1, 2,]`, m: new(pb3.Message)},
+	{in: `key: 1 key: [2, 3] key: 4`, m: new(pb3.Message)},
+	{in: `r_funny: [PUNS, 2]`, m: new(pb3.Message)},
+	{in: `children: []`, m: new(pb3.Message)},
+	{in: `children: [{name: "a"}, <name: "b">]`, m: new(pb3.Message)},
+	{in: `children [{}]`, m: new(pb3.Message)},
+	{in: `children: [{name: "a"} {name: "b"}]`, m: new(pb3.Message)},
+
+	// Maps.
+	{in: `terrain {key: "a"} terrain {key: "a" value {cute: true}}`, m: new(pb3.Message)},
+	{in: `terrain: [{key: "a" value {bunny: "b"}}, {key: "c"}]`, m: new(pb3.Message)},
+	{in: `terrain: []`, m: new(pb3.Message)},
+	{in: `terrain {value {bunny: "b"} key: "a"}`, m: new(pb3.Message)},
+	{in: `terrain {key: "a" key: "b"}`, m: new(pb3.Message)},
+	{in: `terrain {key "a"}`, m: new(pb3.Message)},
+
+	// Messages and separators.
+	{in: `nested: {bunny: "x"}`, m: new(pb3.Message)},
+	{in: `nested {bunny: "x"};`, m: new(pb3.Message)},
+	{in: `nested <bunny: "x">`, m: new(pb3.Message)},
+	{in: `nested {bunny: "x">`, m: new(pb3.Message)},
+	{in: `name: "a"; score: 1,`, m: new(pb3.Message)},
+	{in: `name: "a";; score: 1`, m: new(pb3.Message)},
+	{in: `name: "a" name: "b"`, m: new(pb3.Message)},
+	{in: `name "a"`, m: new(pb3.Message)},
+	{in: `score: 1.5 # comment`, m: new(pb3.Message)},
+	{in: "# comment\nname: \"x\"", m: new(pb3.Message)},
+	{in: `unknown_field: 1`, m: new(pb3.Message)},
+	{in: `SomeGroup {group_field: 1} count: 1`, m: new(pb2.MyMessage)},
+	{in: `somegroup {group_field: 1} count: 1`, m: new(pb2.MyMessage)},
+
+	// Extensions and Any.
+	{in: `count: 1 [proto2_test.Ext.more] {data: "x"}`, m: new(pb2.MyMessage)},
+	{in: `count: 1 [proto2_test.Ext.more]: {data: "x"}`, m: new(pb2.MyMessage)},
+	{in: `count: 1 [proto2_test.no_such_ext]: 1`, m: new(pb2.MyMessage)},
+	{in: `anything: {[type.googleapis.com/proto3_test.Nested] {bunny: "x"}}`, m: new(pb3.Message)},
+	{in: `anything {[type.googleapis.com/proto3_test.Nested]: <>}`, m: new(pb3.Message)},
+	{in: `anything {[type.googleapis.com/proto3_test.NoSuchType] {}}`, m: new(pb3.Message)},
+	{in: `[type.googleapis.com/proto3_test.Nested] {}`, m: new(pb3.Message)},
+}
+
+// textLenientTests is a corpus of text format inputs that UnmarshalText
+// accepts for compatibility with the C++ parser, even though they are
+// rejected by the prototext package.
+var textLenientTests = []struct {
+	in   string
+	want proto.Message
+}{
+	{in: `score: +1`, want: &pb3.Message{Score: 1}},
+	{in: `result_count: +5`, want: &pb3.Message{ResultCount: 5}},
+	{in: `score: - inf`, want: &pb3.Message{Score: float32(math.Inf(-1))}},
+	{in: `score: +inf`, want: &pb3.Message{Score: float32(math.Inf(1))}},
+	{in: `score: +Inf`, want: &pb3.Message{Score: float32(math.Inf(1))}},
+	{in: `score: +infinity`, want: &pb3.Message{Score: float32(math.Inf(1))}},
+	{in: `score: +1.5f`, want: &pb3.Message{Score: 1.5}},
+	{in: `short_key: 0xffffffff`, want: &pb3.Message{ShortKey: []int32{-1}}},
+}
+
+// textStrictTests is a corpus of text format inputs that UnmarshalText
+// rejects for compatibility with earlier versions of this package,
+// even though they are accepted by the prototext package.
+var textStrictTests = []struct {
+	in string
+	m  proto.Message
+}{
+	// Out of range floating-point numbers are not rounded to infinity.
+	{in: `f: 1e309`, m: new(pb2.FloatingPoint)},
+	{in: `others {weight: 1e39}`, m: new(pb2.MyMessage)},
+}
+
+func TestTextConformance(t *testing.T) {
+	for _, tt := range textConformanceTests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := proto.Clone(tt.m)
+			gotErr := proto.UnmarshalText(tt.in, got)
+			want := proto.Clone(tt.m)
+			wantErr := prototext.Unmarshal([]byte(tt.in), proto.MessageV2(want))
+			switch {
+			case gotErr != nil && wantErr == nil:
+				t.Errorf("proto.UnmarshalText(%q) error: %v\nprototext.Unmarshal succeeded with: %v", tt.in, gotErr, want)
+			case gotErr == nil && wantErr != nil:
+				t.Errorf("proto.UnmarshalText(%q) succeeded with: %v\nprototext.Unmarshal error: %v", tt.in, got, wantErr)
+			case gotErr == nil && !proto.Equal(got, want):
+				t.Errorf("proto.UnmarshalText(%q) mismatch:\ngot:  %v\nwant: %v", tt.in, got, want)
+			}
+		})
+	}
+}
+
+func TestTextStrict(t *testing.T) {
+	for _, tt := range textStrictTests {
+		if err := proto.UnmarshalText(tt.in, tt.m); err == nil {
+			t.Errorf("proto.UnmarshalText(%q) succeeded, want error", tt.in)
+		}
+	}
+}
+
+func TestTextLenient(t *testing.T) {
+	for _, tt := range textLenientTests {
+		got := proto.Clone(tt.want)
+		if err := proto.UnmarshalText(tt.in, got); err != nil {
+			t.Errorf("proto.UnmarshalText(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("proto.UnmarshalText(%q) mismatch:\ngot:  %v\nwant: %v", tt.in, got, tt.want)
+		}
+	}
+}
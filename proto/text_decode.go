@@ -8,25 +8,48 @@ import (
 	"encoding"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf16"
 	"unicode/utf8"
 
 	"google.golang.org/protobuf/encoding/prototext"
 	protoV2 "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 const wrapTextUnmarshalV2 = false
 
 // ParseError is returned by UnmarshalText.
+//
+// Callers that need the position of a syntax error (for example, to
+// highlight it in an editor) may retrieve it with errors.As:
+//
+//	var pe *proto.ParseError
+//	if errors.As(err, &pe) {
+//		fmt.Printf("%d:%d: %s: %s\n", pe.Line, pe.Column, pe.Path, pe.Message)
+//	}
 type ParseError struct {
 	Message string
 
-	// Deprecated: Do not use.
-	Line, Offset int
+	// Line is the 1-based line number of the offending token.
+	Line int
+	// Offset is the 0-based byte offset of the offending token,
+	// counted from the start of the input (not the start of the line).
+	Offset int
+	// Column is the 1-based byte column of the offending token within Line.
+	Column int
+	// Path is the path of the field being parsed when the error occurred
+	// (e.g., "server.listeners[2].port"). Extension fields and expanded
+	// Any messages are written in parentheses (e.g., "(pkg.ext).field").
+	// It is empty if the error occurred at the top-level message.
+	Path string
 }
 
 func (e *ParseError) Error() string {
@@ -44,20 +67,55 @@ func UnmarshalText(s string, m Message) error {
 	if u, ok := m.(encoding.TextUnmarshaler); ok {
 		return u.UnmarshalText([]byte(s))
 	}
+	return unmarshalText(newTextParser(s), m)
+}
+
+// UnmarshalTextReader parses proto text formatted input read from r into m.
+//
+// Unlike UnmarshalText, the input is tokenized incrementally and only
+// the portion of the input needed for the current token is buffered,
+// so that large inputs need not be held in memory in their entirety.
+// If reading from r fails, the read error is returned.
+func UnmarshalTextReader(r io.Reader, m Message) error {
+	if u, ok := m.(encoding.TextUnmarshaler); ok {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalText(b)
+	}
+	if wrapTextUnmarshalV2 {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return unmarshalText(newTextParser(string(b)), m)
+	}
+	p := newTextParser("")
+	p.r = r
+	return unmarshalText(p, m)
+}
 
+func unmarshalText(p *textParser, m Message) error {
 	m.Reset()
 	mi := MessageV2(m)
 
 	if wrapTextUnmarshalV2 {
 		err := prototext.UnmarshalOptions{
 			AllowPartial: true,
-		}.Unmarshal([]byte(s), mi)
+		}.Unmarshal([]byte(p.s), mi)
 		if err != nil {
 			return &ParseError{Message: err.Error()}
 		}
 		return checkRequiredNotSet(mi)
 	} else {
-		if err := newTextParser(s).unmarshalMessage(mi.ProtoReflect(), ""); err != nil {
+		err := p.unmarshalMessage(mi.ProtoReflect(), "")
+		// A read error ends the input early, which may leave a message
+		// that parses without error but is truncated.
+		if p.rerr != nil {
+			return p.rerr
+		}
+		if err != nil {
 			return err
 		}
 		return checkRequiredNotSet(mi)
@@ -65,10 +123,15 @@ func UnmarshalText(s string, m Message) error {
 }
 
 type textParser struct {
-	s            string // remaining input
-	done         bool   // whether the parsing is finished (success or error)
-	backed       bool   // whether back() was called
+	s            string    // remaining input
+	r            io.Reader // source of further input; nil once exhausted
+	rerr         error     // error encountered reading from r
+	done         bool      // whether the parsing is finished (success or error)
+	backed       bool      // whether back() was called
 	offset, line int
+	lineStart    int      // byte offset of the start of the current line
+	replay       []token  // recorded tokens to return before further input
+	path         []string // path segments of the field being parsed
 	cur          token
 }
 
@@ -76,6 +139,7 @@ type token struct {
 	value    string
 	err      *ParseError
 	line     int    // line number
+	column   int    // byte number from start of line, starting at 1
 	offset   int    // byte number from start of input, not start of line
 	unquoted string // the unquoted version of value, if it was a quoted string
 }
@@ -85,6 +149,7 @@ func newTextParser(s string) *textParser {
 	p.s = s
 	p.line = 1
 	p.cur.line = 1
+	p.cur.column = 1
 	return p
 }
 
@@ -116,21 +181,19 @@ func (p *textParser) unmarshalMessage(m protoreflect.Message, terminator string)
 
 		// This is a normal, non-extension field.
 		name := protoreflect.Name(tok.value)
-		fd := fds.ByName(name)
-		switch {
-		case fd == nil:
-			gd := fds.ByName(protoreflect.Name(strings.ToLower(string(name))))
-			if gd != nil && gd.Kind() == protoreflect.GroupKind && gd.Message().Name() == name {
-				fd = gd
-			}
-		case fd.Kind() == protoreflect.GroupKind && fd.Message().Name() != name:
-			fd = nil
-		case fd.IsWeak() && fd.Message().IsPlaceholder():
+		// A group field is named by its message type, as the text format
+		// specifies, or by its field name, as the prototext package accepts.
+		fd := fds.ByTextName(string(name))
+		if fd == nil {
+			fd = fds.ByName(name)
+		}
+		if fd != nil && fd.IsWeak() && fd.Message().IsPlaceholder() {
 			fd = nil
 		}
 		if fd == nil {
 			typeName := string(md.FullName())
-			if m, ok := m.Interface().(Message); ok {
+			// Dynamic messages have no Go type of their own.
+			if m, ok := m.Interface().(Message); ok && !isDynamic(m) {
 				t := reflect.TypeOf(m)
 				if t.Kind() == reflect.Ptr {
 					typeName = t.Elem().String()
@@ -156,9 +219,11 @@ func (p *textParser) unmarshalMessage(m protoreflect.Message, terminator string)
 		if !m.Has(fd) && (fd.IsList() || fd.IsMap() || fd.Message() != nil) {
 			v = m.Mutable(fd)
 		}
+		p.pushPath(fieldPathName(fd))
 		if v, err = p.unmarshalValue(v, fd); err != nil {
 			return err
 		}
+		p.popPath()
 		m.Set(fd, v)
 
 		if err := p.consumeOptionalSeparator(); err != nil {
@@ -176,6 +241,9 @@ func (p *textParser) unmarshalExtensionOrAny(m protoreflect.Message, seen map[pr
 
 	// If it contains a slash, it's an Any type URL.
 	if slashIdx := strings.LastIndex(name, "/"); slashIdx >= 0 {
+		if m.Descriptor().FullName() != "google.protobuf.Any" {
+			return p.errorf("type URL %q used in message %q, which is not google.protobuf.Any", name, m.Descriptor().FullName())
+		}
 		tok := p.next()
 		if tok.err != nil {
 			return tok.err
@@ -203,9 +271,11 @@ func (p *textParser) unmarshalExtensionOrAny(m protoreflect.Message, seen map[pr
 			return p.errorf("unrecognized message %q in google.protobuf.Any", name[slashIdx+len("/"):])
 		}
 		m2 := mt.New()
+		p.pushPath("(" + name + ")")
 		if err := p.unmarshalMessage(m2, terminator); err != nil {
 			return err
 		}
+		p.popPath()
 		b, err := protoV2.Marshal(m2.Interface())
 		if err != nil {
 			return p.errorf("failed to marshal message of type %q: %v", name[slashIdx+len("/"):], err)
@@ -247,10 +317,12 @@ func (p *textParser) unmarshalExtensionOrAny(m protoreflect.Message, seen map[pr
 	if !m.Has(fd) && (fd.IsList() || fd.IsMap() || fd.Message() != nil) {
 		v = m.Mutable(fd)
 	}
+	p.pushPath(fieldPathName(fd))
 	v, err = p.unmarshalValue(v, fd)
 	if err != nil {
 		return err
 	}
+	p.popPath()
 	m.Set(fd, v)
 	return p.consumeOptionalSeparator()
 }
@@ -270,12 +342,17 @@ func (p *textParser) unmarshalValue(v protoreflect.Value, fd protoreflect.FieldD
 		var err error
 		if tok.value == "[" {
 			// Repeated field with list notation, like [1,2,3].
+			if p.consumeListEnd() {
+				return v, nil
+			}
 			for {
 				vv := lv.NewElement()
+				p.pushPath(indexPathSegment(lv.Len()))
 				vv, err = p.unmarshalSingularValue(vv, fd)
 				if err != nil {
 					return v, err
 				}
+				p.popPath()
 				lv.Append(vv)
 
 				tok := p.next()
@@ -295,76 +372,186 @@ func (p *textParser) unmarshalValue(v protoreflect.Value, fd protoreflect.FieldD
 		// One value of the repeated field.
 		p.back()
 		vv := lv.NewElement()
+		p.pushPath(indexPathSegment(lv.Len()))
 		vv, err = p.unmarshalSingularValue(vv, fd)
 		if err != nil {
 			return v, err
 		}
+		p.popPath()
 		lv.Append(vv)
 		return v, nil
 	case fd.IsMap():
-		// The map entry should be this sequence of tokens:
-		//	< key : KEY value : VALUE >
-		// However, implementations may omit key or value, and technically
-		// we should support them in any order.
-		var terminator string
-		switch tok.value {
-		case "<":
-			terminator = ">"
-		case "{":
-			terminator = "}"
-		default:
-			return v, p.errorf("expected '{' or '<', found %q", tok.value)
-		}
-
-		keyFD := fd.MapKey()
-		valFD := fd.MapValue()
-
 		mv := v.Map()
-		kv := keyFD.Default()
-		vv := mv.NewValue()
-		for {
-			tok := p.next()
-			if tok.err != nil {
-				return v, tok.err
-			}
-			if tok.value == terminator {
-				break
+		if tok.value == "[" {
+			// Map field with list notation, like [{key: 1}, {key: 2}].
+			if p.consumeListEnd() {
+				return v, nil
 			}
-			var err error
-			switch tok.value {
-			case "key":
-				if err := p.consumeToken(":"); err != nil {
-					return v, err
-				}
-				if kv, err = p.unmarshalSingularValue(kv, keyFD); err != nil {
-					return v, err
+			for {
+				tok := p.next()
+				if tok.err != nil {
+					return v, tok.err
 				}
-				if err := p.consumeOptionalSeparator(); err != nil {
+				if err := p.unmarshalMapEntry(mv, fd, tok.value); err != nil {
 					return v, err
 				}
-			case "value":
-				if err := p.checkForColon(valFD); err != nil {
-					return v, err
+
+				tok = p.next()
+				if tok.err != nil {
+					return v, tok.err
 				}
-				if vv, err = p.unmarshalSingularValue(vv, valFD); err != nil {
-					return v, err
+				if tok.value == "]" {
+					break
 				}
-				if err := p.consumeOptionalSeparator(); err != nil {
-					return v, err
+				if tok.value != "," {
+					return v, p.errorf("Expected ']' or ',' found %q", tok.value)
 				}
-			default:
-				p.back()
-				return v, p.errorf(`expected "key", "value", or %q, found %q`, terminator, tok.value)
 			}
+			return v, nil
 		}
-		mv.Set(kv.MapKey(), vv)
-		return v, nil
+		return v, p.unmarshalMapEntry(mv, fd, tok.value)
 	default:
 		p.back()
 		return p.unmarshalSingularValue(v, fd)
 	}
 }
 
+// unmarshalMapEntry parses a single map entry into mv,
+// where open is the already consumed opening delimiter of the entry.
+func (p *textParser) unmarshalMapEntry(mv protoreflect.Map, fd protoreflect.FieldDescriptor, open string) error {
+	// The map entry should be this sequence of tokens:
+	//	< key : KEY value : VALUE >
+	// However, implementations may omit key or value, and technically
+	// we should support them in any order.
+	var terminator string
+	switch open {
+	case "<":
+		terminator = ">"
+	case "{":
+		terminator = "}"
+	default:
+		return p.errorf("expected '{' or '<', found %q", open)
+	}
+
+	keyFD := fd.MapKey()
+	valFD := fd.MapValue()
+
+	kv := keyFD.Default()
+	vv := mv.NewValue()
+	hasKey, hasValue := false, false
+	var valueTokens []token // a value that precedes the key
+	for {
+		tok := p.next()
+		if tok.err != nil {
+			return tok.err
+		}
+		if tok.value == terminator {
+			break
+		}
+		var err error
+		switch tok.value {
+		case "key":
+			if hasKey {
+				return p.errorf("non-repeated field %q was repeated", keyFD.Name())
+			}
+			if err := p.consumeToken(":"); err != nil {
+				return err
+			}
+			if kv, err = p.unmarshalSingularValue(kv, keyFD); err != nil {
+				return err
+			}
+			hasKey = true
+			if err := p.consumeOptionalSeparator(); err != nil {
+				return err
+			}
+		case "value":
+			if hasValue {
+				return p.errorf("non-repeated field %q was repeated", valFD.Name())
+			}
+			hasValue = true
+			if err := p.checkForColon(valFD); err != nil {
+				return err
+			}
+			if hasKey {
+				vv, err = p.unmarshalMapValue(vv, valFD, mapKeyPathSegment(kv.MapKey(), keyFD))
+			} else {
+				// Parse the value once the key is known,
+				// so that errors in it report the key.
+				valueTokens, err = p.recordValue()
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.consumeOptionalSeparator(); err != nil {
+				return err
+			}
+		default:
+			p.back()
+			return p.errorf(`expected "key", "value", or %q, found %q`, terminator, tok.value)
+		}
+	}
+	if valueTokens != nil {
+		key := ""
+		if hasKey {
+			key = mapKeyPathSegment(kv.MapKey(), keyFD)
+		}
+		p.replay = valueTokens
+		var err error
+		if vv, err = p.unmarshalMapValue(vv, valFD, key); err != nil {
+			return err
+		}
+	}
+	mv.Set(kv.MapKey(), vv)
+	return nil
+}
+
+// unmarshalMapValue parses the value of a map entry with the given
+// formatted key, which is empty if the entry has no key.
+func (p *textParser) unmarshalMapValue(v protoreflect.Value, fd protoreflect.FieldDescriptor, key string) (protoreflect.Value, error) {
+	if key != "" {
+		p.pushPath(key)
+	}
+	v, err := p.unmarshalSingularValue(v, fd)
+	if err != nil {
+		return v, err
+	}
+	if key != "" {
+		p.popPath()
+	}
+	return v, nil
+}
+
+// recordValue consumes the tokens of a single value, which is either
+// a scalar or a message in braces or angle brackets, and returns them
+// for the parser to replay.
+func (p *textParser) recordValue() ([]token, error) {
+	var toks []token
+	depth := 0
+	for {
+		tok := p.next()
+		if tok.err != nil {
+			return nil, tok.err
+		}
+		if tok.value == "" {
+			return nil, p.errorf("unexpected EOF")
+		}
+		switch tok.value {
+		case "{", "<", "[":
+			depth++
+		case "}", ">", "]":
+			if depth == 0 {
+				p.back()
+				return nil, p.errorf("expected a value, found %q", tok.value)
+			}
+			depth--
+		}
+		toks = append(toks, *tok)
+		if depth == 0 {
+			return toks, nil
+		}
+	}
+}
+
 func (p *textParser) unmarshalSingularValue(v protoreflect.Value, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
 	tok := p.next()
 	if tok.err != nil {
@@ -382,8 +569,11 @@ func (p *textParser) unmarshalSingularValue(v protoreflect.Value, fd protoreflec
 		case "false", "0", "f", "False":
 			return protoreflect.ValueOfBool(false), nil
 		}
+		if x, err := parseTextUint(tok.value, 64); err == nil && x <= 1 {
+			return protoreflect.ValueOfBool(x == 1), nil
+		}
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
-		if x, err := strconv.ParseInt(tok.value, 0, 32); err == nil {
+		if x, err := parseTextInt(tok.value, 32); err == nil {
 			return protoreflect.ValueOfInt32(int32(x)), nil
 		}
 
@@ -391,12 +581,12 @@ func (p *textParser) unmarshalSingularValue(v protoreflect.Value, fd protoreflec
 		// two's complement arithmetic to represent negative numbers.
 		// This feature is here for backwards compatibility with C++.
 		if strings.HasPrefix(tok.value, "0x") {
-			if x, err := strconv.ParseUint(tok.value, 0, 32); err == nil {
+			if x, err := parseTextUint(tok.value, 32); err == nil {
 				return protoreflect.ValueOfInt32(int32(-(int64(^x) + 1))), nil
 			}
 		}
 	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
-		if x, err := strconv.ParseInt(tok.value, 0, 64); err == nil {
+		if x, err := parseTextInt(tok.value, 64); err == nil {
 			return protoreflect.ValueOfInt64(int64(x)), nil
 		}
 
@@ -404,36 +594,26 @@ func (p *textParser) unmarshalSingularValue(v protoreflect.Value, fd protoreflec
 		// two's complement arithmetic to represent negative numbers.
 		// This feature is here for backwards compatibility with C++.
 		if strings.HasPrefix(tok.value, "0x") {
-			if x, err := strconv.ParseUint(tok.value, 0, 64); err == nil {
+			if x, err := parseTextUint(tok.value, 64); err == nil {
 				return protoreflect.ValueOfInt64(int64(-(int64(^x) + 1))), nil
 			}
 		}
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		if x, err := strconv.ParseUint(tok.value, 0, 32); err == nil {
+		if x, err := parseTextUint(tok.value, 32); err == nil {
 			return protoreflect.ValueOfUint32(uint32(x)), nil
 		}
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		if x, err := strconv.ParseUint(tok.value, 0, 64); err == nil {
+		if x, err := parseTextUint(tok.value, 64); err == nil {
 			return protoreflect.ValueOfUint64(uint64(x)), nil
 		}
 	case protoreflect.FloatKind:
-		// Ignore 'f' for compatibility with output generated by C++,
-		// but don't remove 'f' when the value is "-inf" or "inf".
-		v := tok.value
-		if strings.HasSuffix(v, "f") && v != "-inf" && v != "inf" {
-			v = v[:len(v)-len("f")]
-		}
-		if x, err := strconv.ParseFloat(v, 32); err == nil {
+		v := trimFloatSuffix(tok.value)
+		if x, err := parseTextFloat(v, 32); err == nil {
 			return protoreflect.ValueOfFloat32(float32(x)), nil
 		}
 	case protoreflect.DoubleKind:
-		// Ignore 'f' for compatibility with output generated by C++,
-		// but don't remove 'f' when the value is "-inf" or "inf".
-		v := tok.value
-		if strings.HasSuffix(v, "f") && v != "-inf" && v != "inf" {
-			v = v[:len(v)-len("f")]
-		}
-		if x, err := strconv.ParseFloat(v, 64); err == nil {
+		v := trimFloatSuffix(tok.value)
+		if x, err := parseTextFloat(v, 64); err == nil {
 			return protoreflect.ValueOfFloat64(float64(x)), nil
 		}
 	case protoreflect.StringKind:
@@ -445,7 +625,7 @@ func (p *textParser) unmarshalSingularValue(v protoreflect.Value, fd protoreflec
 			return protoreflect.ValueOfBytes([]byte(tok.unquoted)), nil
 		}
 	case protoreflect.EnumKind:
-		if x, err := strconv.ParseInt(tok.value, 0, 32); err == nil {
+		if x, err := parseTextInt(tok.value, 32); err == nil {
 			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(x)), nil
 		}
 		vd := fd.Enum().Values().ByName(protoreflect.Name(tok.value))
@@ -498,7 +678,7 @@ func (p *textParser) consumeExtensionOrAnyName() (string, error) {
 	if len(tok.value) > 2 && isQuote(tok.value[0]) && tok.value[len(tok.value)-1] == tok.value[0] {
 		name, err := unquoteC(tok.value[1:len(tok.value)-1], rune(tok.value[0]))
 		if err != nil {
-			return "", err
+			return "", p.errorf("invalid quoted string %s: %v", tok.value, err)
 		}
 		return name, p.consumeToken("]")
 	}
@@ -518,6 +698,17 @@ func (p *textParser) consumeExtensionOrAnyName() (string, error) {
 	return strings.Join(parts, ""), nil
 }
 
+// consumeListEnd consumes the ']' of an empty list,
+// reporting whether the list was empty.
+func (p *textParser) consumeListEnd() bool {
+	tok := p.next()
+	if tok.err == nil && tok.value == "]" {
+		return true
+	}
+	p.back()
+	return false
+}
+
 // consumeOptionalSeparator consumes an optional semicolon or comma.
 // It is used in unmarshalMessage to provide backward compatibility.
 func (p *textParser) consumeOptionalSeparator() error {
@@ -532,26 +723,81 @@ func (p *textParser) consumeOptionalSeparator() error {
 }
 
 func (p *textParser) errorf(format string, a ...interface{}) *ParseError {
-	pe := &ParseError{fmt.Sprintf(format, a...), p.cur.line, p.cur.offset}
+	pe := &ParseError{
+		Message: fmt.Sprintf(format, a...),
+		Line:    p.cur.line,
+		Offset:  p.cur.offset,
+		Column:  p.cur.column,
+		Path:    p.pathString(),
+	}
 	p.cur.err = pe
 	p.done = true
 	return pe
 }
 
+// pushPath appends a segment to the path of the field being parsed.
+// A segment is either a field name, a bracketed list index or map key,
+// or a parenthesized extension name or Any type URL.
+func (p *textParser) pushPath(s string) { p.path = append(p.path, s) }
+
+// popPath removes the last segment pushed by pushPath.
+func (p *textParser) popPath() { p.path = p.path[:len(p.path)-1] }
+
+// pathString formats the path of the field being parsed.
+func (p *textParser) pathString() string {
+	var b strings.Builder
+	for i, s := range p.path {
+		if i > 0 && s[0] != '[' {
+			b.WriteByte('.')
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// textReadSize is the minimum number of bytes read from the
+// underlying reader at a time by a streaming textParser.
+const textReadSize = 32 << 10
+
+// ensure reports whether the remaining input holds more than i bytes,
+// reading further input from p.r as necessary.
+// Reading preserves p.s as a prefix so that indexes into it remain valid.
+func (p *textParser) ensure(i int) bool {
+	for i >= len(p.s) && p.r != nil {
+		// Read at least as much as is already buffered
+		// so that long tokens are accumulated in amortized linear time.
+		n := textReadSize
+		if len(p.s) > n {
+			n = len(p.s)
+		}
+		buf := make([]byte, n)
+		n, err := io.ReadAtLeast(p.r, buf, 1)
+		p.s += string(buf[:n])
+		if err != nil {
+			if err != io.EOF {
+				p.rerr = err
+			}
+			p.r = nil
+		}
+	}
+	return i < len(p.s)
+}
+
 func (p *textParser) skipWhitespace() {
 	i := 0
-	for i < len(p.s) && (isWhitespace(p.s[i]) || p.s[i] == '#') {
+	for p.ensure(i) && (isWhitespace(p.s[i]) || p.s[i] == '#') {
 		if p.s[i] == '#' {
 			// comment; skip to end of line or input
-			for i < len(p.s) && p.s[i] != '\n' {
+			for p.ensure(i) && p.s[i] != '\n' {
 				i++
 			}
-			if i == len(p.s) {
+			if !p.ensure(i) {
 				break
 			}
 		}
 		if p.s[i] == '\n' {
 			p.line++
+			p.lineStart = p.offset + i + 1
 		}
 		i++
 	}
@@ -572,6 +818,7 @@ func (p *textParser) advance() {
 	// Start of non-whitespace
 	p.cur.err = nil
 	p.cur.offset, p.cur.line = p.offset, p.line
+	p.cur.column = p.offset - p.lineStart + 1
 	p.cur.unquoted = ""
 	switch p.s[0] {
 	case '<', '>', '{', '}', ':', '[', ']', ';', ',', '/':
@@ -580,14 +827,14 @@ func (p *textParser) advance() {
 	case '"', '\'':
 		// Quoted string
 		i := 1
-		for i < len(p.s) && p.s[i] != p.s[0] && p.s[i] != '\n' {
-			if p.s[i] == '\\' && i+1 < len(p.s) {
+		for p.ensure(i) && p.s[i] != p.s[0] && p.s[i] != '\n' {
+			if p.s[i] == '\\' && p.ensure(i+1) {
 				// skip escaped char
 				i++
 			}
 			i++
 		}
-		if i >= len(p.s) || p.s[i] != p.s[0] {
+		if !p.ensure(i) || p.s[i] != p.s[0] {
 			p.errorf("unmatched quote")
 			return
 		}
@@ -600,7 +847,7 @@ func (p *textParser) advance() {
 		p.cur.unquoted = unq
 	default:
 		i := 0
-		for i < len(p.s) && isIdentOrNumberChar(p.s[i]) {
+		for p.ensure(i) && isIdentOrNumberChar(p.s[i]) {
 			i++
 		}
 		if i == 0 {
@@ -618,10 +865,17 @@ func (p *textParser) back() { p.backed = true }
 
 // Advances the parser and returns the new current token.
 func (p *textParser) next() *token {
-	if p.backed || p.done {
+	if p.backed {
 		p.backed = false
 		return &p.cur
 	}
+	if len(p.replay) > 0 {
+		p.cur, p.replay = p.replay[0], p.replay[1:]
+		return &p.cur
+	}
+	if p.done {
+		return &p.cur
+	}
 	p.advance()
 	if p.done {
 		p.cur.value = ""
@@ -643,6 +897,20 @@ func (p *textParser) next() *token {
 		}
 		p.done = false // parser may have seen EOF, but we want to return cat
 		p.cur = cat
+	} else if p.cur.value == "-" {
+		// A minus sign may be separated from the number it negates
+		// by whitespace or comments.
+		neg := p.cur
+		p.skipWhitespace()
+		if !p.done && isIdentOrNumberChar(p.s[0]) {
+			p.advance()
+			if p.cur.err != nil {
+				return &p.cur
+			}
+			neg.value += p.cur.value
+		}
+		p.done = false // parser may have seen EOF, but we want to return neg
+		p.cur = neg
 	}
 	return &p.cur
 }
@@ -730,24 +998,36 @@ func unescape(s string) (ch string, tail string, err error) {
 	case '\'', '"', '\\':
 		return string(r), s, nil
 	case '0', '1', '2', '3', '4', '5', '6', '7':
-		if len(s) < 2 {
-			return "", "", fmt.Errorf(`\%c requires 2 following digits`, r)
+		// One, two, or three octal digits.
+		n := len(s) - len(strings.TrimLeft(s, "01234567"))
+		if n > 2 {
+			n = 2
 		}
-		ss := string(r) + s[:2]
-		s = s[2:]
+		ss := string(r) + s[:n]
+		s = s[n:]
 		i, err := strconv.ParseUint(ss, 8, 8)
 		if err != nil {
-			return "", "", fmt.Errorf(`\%s contains non-octal digits`, ss)
+			return "", "", fmt.Errorf(`\%s is not a valid octal escape`, ss)
 		}
 		return string([]byte{byte(i)}), s, nil
-	case 'x', 'X', 'u', 'U':
-		var n int
-		switch r {
-		case 'x', 'X':
+	case 'x', 'X':
+		// One or two hexadecimal digits.
+		n := len(s) - len(strings.TrimLeft(s, "0123456789abcdefABCDEF"))
+		if n > 2 {
 			n = 2
-		case 'u':
-			n = 4
-		case 'U':
+		}
+		if n == 0 {
+			ss := s
+			if len(ss) > 2 {
+				ss = ss[:2]
+			}
+			return "", "", fmt.Errorf(`\%c%s contains non-hexadecimal digits`, r, ss)
+		}
+		i, _ := strconv.ParseUint(s[:n], 16, 8)
+		return string([]byte{byte(i)}), s[n:], nil
+	case 'u', 'U':
+		n := 4
+		if r == 'U' {
 			n = 8
 		}
 		if len(s) < n {
@@ -759,17 +1039,97 @@ func unescape(s string) (ch string, tail string, err error) {
 		if err != nil {
 			return "", "", fmt.Errorf(`\%c%s contains non-hexadecimal digits`, r, ss)
 		}
-		if r == 'x' || r == 'X' {
-			return string([]byte{byte(i)}), s, nil
-		}
 		if i > utf8.MaxRune {
 			return "", "", fmt.Errorf(`\%c%s is not a valid Unicode code point`, r, ss)
 		}
-		return string(rune(i)), s, nil
+		r1 := rune(i)
+		if utf16.IsSurrogate(r1) {
+			// A surrogate must be immediately followed by its pair.
+			if len(s) < 6 || s[:2] != `\u` {
+				return "", "", fmt.Errorf(`\%c%s is an unpaired surrogate`, r, ss)
+			}
+			i, err := strconv.ParseUint(s[2:6], 16, 64)
+			r1 = utf16.DecodeRune(r1, rune(i))
+			if err != nil || r1 == unicode.ReplacementChar {
+				return "", "", fmt.Errorf(`\%c%s\%s is not a valid surrogate pair`, r, ss, s[1:6])
+			}
+			s = s[6:]
+		}
+		return string(r1), s, nil
 	}
 	return "", "", fmt.Errorf(`unknown escape \%c`, r)
 }
 
+var errTextNumber = errors.New("proto: invalid number syntax")
+
+// Go syntax for numbers accepted by package strconv is a superset of that
+// of the text format. The parseText functions reject the Go-specific syntax,
+// namely digit separators ("1_000"), binary and octal prefixes ("0b1", "0o7"),
+// and hexadecimal floating-point numbers ("0x1p-2").
+
+func parseTextInt(s string, bitSize int) (int64, error) {
+	if !isTextNumber(s) {
+		return 0, errTextNumber
+	}
+	return strconv.ParseInt(s, 0, bitSize)
+}
+
+func parseTextUint(s string, bitSize int) (uint64, error) {
+	if !isTextNumber(s) {
+		return 0, errTextNumber
+	}
+	return strconv.ParseUint(s, 0, bitSize)
+}
+
+func parseTextFloat(s string, bitSize int) (float64, error) {
+	if !isTextNumber(s) || isHexNumber(s) {
+		return 0, errTextNumber
+	}
+	return strconv.ParseFloat(s, bitSize)
+}
+
+func isTextNumber(s string) bool {
+	if strings.IndexByte(s, '_') >= 0 {
+		return false
+	}
+	s = strings.TrimLeft(s, "+-")
+	if len(s) > 1 && s[0] == '0' {
+		switch s[1] {
+		case 'b', 'B', 'o', 'O':
+			return false
+		}
+	}
+	return true
+}
+
+func isHexNumber(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return len(s) > 1 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X')
+}
+
+// trimFloatSuffix removes the 'f' or 'F' suffix from a float literal,
+// which is accepted for compatibility with output generated by C++.
+// It does not remove the trailing 'f' of "inf" or any other spelling of
+// infinity or NaN, with or without a sign, which take no suffix.
+func trimFloatSuffix(v string) string {
+	if !strings.HasSuffix(v, "f") && !strings.HasSuffix(v, "F") {
+		return v
+	}
+	if s := strings.TrimLeft(v, "+-"); s == "" || !isDigitOrDot(s[0]) {
+		return v
+	}
+	return v[:len(v)-len("f")]
+}
+
+func isDigitOrDot(c byte) bool {
+	return '0' <= c && c <= '9' || c == '.'
+}
+
+func isDynamic(m Message) bool {
+	_, ok := m.(*dynamicpb.Message)
+	return ok
+}
+
 func isIdentOrNumberChar(c byte) bool {
 	switch {
 	case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z':
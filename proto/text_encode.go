@@ -18,6 +18,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 const wrapTextMarshalV2 = false
@@ -26,6 +27,29 @@ const wrapTextMarshalV2 = false
 type TextMarshaler struct {
 	Compact   bool // use compact text format (one line)
 	ExpandAny bool // expand google.protobuf.Any messages of known types
+
+	// RedactField, if non-nil, reports whether the value of a field is
+	// sensitive and must not be printed. The values of such fields are
+	// replaced with a "[REDACTED]" placeholder. Use IsDebugRedact to redact
+	// fields marked with the debug_redact field option.
+	// Since it is not known whether unknown fields are sensitive,
+	// their values are also redacted.
+	//
+	// Redacted output is intended for logging and debugging;
+	// it cannot be parsed by UnmarshalText.
+	RedactField func(protoreflect.FieldDescriptor) bool
+
+	// keepUnknown specifies that unknown fields are printed even if
+	// RedactField is set.
+	keepUnknown bool
+}
+
+// IsDebugRedact reports whether fd is marked with the debug_redact option,
+// which indicates that the field holds sensitive data.
+// It is suitable for use as TextMarshaler.RedactField.
+func IsDebugRedact(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	return ok && opts.GetDebugRedact()
 }
 
 // Marshal writes the proto text format of m to w.
@@ -69,9 +93,11 @@ func (tm *TextMarshaler) marshal(m Message) ([]byte, error) {
 		return opts.Marshal(mr.Interface())
 	} else {
 		w := &textWriter{
-			compact:   tm.Compact,
-			expandAny: tm.ExpandAny,
-			complete:  true,
+			compact:       tm.Compact,
+			expandAny:     tm.ExpandAny,
+			redact:        tm.RedactField,
+			redactUnknown: tm.RedactField != nil && !tm.keepUnknown,
+			complete:      true,
 		}
 
 		if m, ok := m.(encoding.TextMarshaler); ok {
@@ -91,19 +117,43 @@ func (tm *TextMarshaler) marshal(m Message) ([]byte, error) {
 var (
 	defaultTextMarshaler = TextMarshaler{}
 	compactTextMarshaler = TextMarshaler{Compact: true}
+
+	// The String variants print unknown fields, which were always printed
+	// and on which existing output, such as that of String methods, relies.
+	redactedTextMarshaler        = TextMarshaler{RedactField: IsDebugRedact, keepUnknown: true}
+	redactedCompactTextMarshaler = TextMarshaler{Compact: true, RedactField: IsDebugRedact, keepUnknown: true}
 )
 
 // MarshalText writes the proto text format of m to w.
+// Fields marked with the debug_redact option are not redacted, so that
+// the output can be parsed by UnmarshalText.
 func MarshalText(w io.Writer, m Message) error { return defaultTextMarshaler.Marshal(w, m) }
 
-// MarshalTextString returns a proto text formatted string of m.
-func MarshalTextString(m Message) string { return defaultTextMarshaler.Text(m) }
+// MarshalTextString returns a proto text formatted string of m,
+// which is intended for logging and debugging. The values of fields marked
+// with the debug_redact option are redacted, but unknown fields are printed;
+// use MarshalText to produce text that can be parsed by UnmarshalText.
+//
+// Compatibility note: earlier versions printed the values of debug_redact
+// fields. Callers that parse the result, store it, or compare it against
+// golden text must switch to MarshalText to keep the previous output.
+func MarshalTextString(m Message) string { return redactedTextMarshaler.Text(m) }
 
 // CompactText writes the compact proto text format of m to w.
+// Fields marked with the debug_redact option are not redacted, so that
+// the output can be parsed by UnmarshalText.
 func CompactText(w io.Writer, m Message) error { return compactTextMarshaler.Marshal(w, m) }
 
-// CompactTextString returns a compact proto text formatted string of m.
-func CompactTextString(m Message) string { return compactTextMarshaler.Text(m) }
+// CompactTextString returns a compact proto text formatted string of m,
+// which is intended for logging and debugging, such as by the String methods
+// of generated messages. The values of fields marked with the debug_redact
+// option are redacted, but unknown fields are printed; use CompactText to
+// produce text that can be parsed by UnmarshalText.
+//
+// Compatibility note: earlier versions printed the values of debug_redact
+// fields, and so did the String methods of generated messages. Callers that
+// parse the result must switch to CompactText to keep the previous output.
+func CompactTextString(m Message) string { return redactedCompactTextMarshaler.Text(m) }
 
 var (
 	newline         = []byte("\n")
@@ -111,15 +161,23 @@ var (
 	posInf          = []byte("inf")
 	negInf          = []byte("-inf")
 	nan             = []byte("nan")
+	redacted        = []byte("[REDACTED]")
 )
 
 // textWriter is an io.Writer that tracks its indentation level.
 type textWriter struct {
-	compact   bool // same as TextMarshaler.Compact
-	expandAny bool // same as TextMarshaler.ExpandAny
-	complete  bool // whether the current position is a complete line
-	indent    int  // indentation level; never negative
-	buf       []byte
+	compact       bool                                    // same as TextMarshaler.Compact
+	expandAny     bool                                    // same as TextMarshaler.ExpandAny
+	redact        func(protoreflect.FieldDescriptor) bool // same as TextMarshaler.RedactField
+	redactUnknown bool                                    // whether to redact the values of unknown fields
+	complete      bool                                    // whether the current position is a complete line
+	indent        int                                     // indentation level; never negative
+	buf           []byte
+}
+
+// isRedacted reports whether the value of fd must be replaced by a placeholder.
+func (w *textWriter) isRedacted(fd protoreflect.FieldDescriptor) bool {
+	return w.redact != nil && w.redact(fd)
 }
 
 func (w *textWriter) Write(p []byte) (n int, _ error) {
@@ -280,6 +338,10 @@ func (w *textWriter) writeMessage(m protoreflect.Message) error {
 		}
 
 		switch {
+		case w.isRedacted(fd):
+			w.writeName(fd)
+			w.Write(redacted)
+			w.WriteByte('\n')
 		case fd.IsList():
 			lv := m.Get(fd).List()
 			for j := 0; j < lv.Len(); j++ {
@@ -453,6 +515,16 @@ func (w *textWriter) writeUnknownFields(b []byte) {
 		if !w.compact || wtyp == protowire.StartGroupType {
 			w.WriteByte(' ')
 		}
+		if w.redactUnknown && wtyp != protowire.StartGroupType {
+			n := protowire.ConsumeFieldValue(num, wtyp, b)
+			if n < 0 {
+				return
+			}
+			b = b[n:]
+			w.Write(redacted)
+			w.WriteByte('\n')
+			continue
+		}
 		switch wtyp {
 		case protowire.VarintType:
 			v, n := protowire.ConsumeVarint(b)
@@ -521,7 +593,14 @@ func (w *textWriter) writeExtensions(m protoreflect.Message) error {
 			name = strings.TrimSuffix(name, ".message_set_extension")
 		}
 
-		if !ext.desc.IsList() {
+		if w.isRedacted(ext.desc) {
+			fmt.Fprintf(w, "[%s]:", name)
+			if !w.compact {
+				w.WriteByte(' ')
+			}
+			w.Write(redacted)
+			w.WriteByte('\n')
+		} else if !ext.desc.IsList() {
 			if err := w.writeSingularExtension(name, ext.val, ext.desc); err != nil {
 				return err
 			}
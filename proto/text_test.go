@@ -7,13 +7,19 @@ package proto_test
 import (
 	"bytes"
 	"errors"
+	"io"
 	"math"
 	"strings"
 	"sync"
 	"testing"
+	"testing/iotest"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 
 	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
 	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
@@ -417,6 +423,89 @@ func TestMarshalTextCustomMessage(t *testing.T) {
 		t.Errorf("got:\n%v\n\nwant:\n%v", got, "custom")
 	}
 }
+
+func TestMarshalTextRedact(t *testing.T) {
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("redact.proto"),
+		Package: proto.String("redact"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Login"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:   proto.String("user"),
+				Number: proto.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}, {
+				Name:    proto.String("password"),
+				Number:  proto.Int32(2),
+				Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:    descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Options: &descriptorpb.FieldOptions{DebugRedact: proto.Bool(true)},
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := fd.Messages().Get(0)
+	login := dynamicpb.NewMessage(md)
+	login.Set(md.Fields().ByName("user"), protoreflect.ValueOfString("gopher"))
+	login.Set(md.Fields().ByName("password"), protoreflect.ValueOfString("hunter2"))
+
+	tm := proto.TextMarshaler{RedactField: proto.IsDebugRedact}
+	if got, want := tm.Text(proto.MessageV1(login)), "user: \"gopher\"\npassword: [REDACTED]\n"; got != want {
+		t.Errorf("TextMarshaler.Text() with debug_redact:\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := proto.MarshalTextString(proto.MessageV1(login)), "user: \"gopher\"\npassword: [REDACTED]\n"; got != want {
+		t.Errorf("MarshalTextString() with debug_redact:\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := proto.CompactTextString(proto.MessageV1(login)), `user:"gopher" password:[REDACTED] `; got != want {
+		t.Errorf("CompactTextString() with debug_redact:\ngot:  %q\nwant: %q", got, want)
+	}
+	var buf bytes.Buffer
+	if err := proto.CompactText(&buf, proto.MessageV1(login)); err != nil {
+		t.Fatalf("CompactText error: %v", err)
+	}
+	if got, want := buf.String(), `user:"gopher" password:"hunter2" `; got != want {
+		t.Errorf("CompactText() with debug_redact:\ngot:  %q\nwant: %q", got, want)
+	}
+
+	// Unknown fields are redacted by a TextMarshaler with RedactField,
+	// but printed by CompactTextString.
+	login.SetUnknown([]byte("\x18\x05\x22\x06secret"))
+	tm.Compact = true
+	if got, want := tm.Text(proto.MessageV1(login)), `user:"gopher" password:[REDACTED] 3:[REDACTED] 4:[REDACTED] `; got != want {
+		t.Errorf("TextMarshaler.Text() with unknown fields:\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := proto.CompactTextString(proto.MessageV1(login)), `user:"gopher" password:[REDACTED] 3:5 4:"secret" `; got != want {
+		t.Errorf("CompactTextString() with unknown fields:\ngot:  %q\nwant: %q", got, want)
+	}
+
+	m := &pb2.MyMessage{
+		Count:  proto.Int32(42),
+		Pet:    []string{"bunny", "kitty"},
+		Inner:  &pb2.InnerMessage{Host: proto.String("secret.example.com")},
+		Others: []*pb2.OtherMessage{{Inner: &pb2.InnerMessage{Host: proto.String("hidden")}}},
+	}
+	if err := proto.SetExtension(m, pb2.E_Greeting, []string{"hello"}); err != nil {
+		t.Fatal(err)
+	}
+	tm = proto.TextMarshaler{
+		Compact: true,
+		RedactField: func(fd protoreflect.FieldDescriptor) bool {
+			switch fd.Name() {
+			case "host", "pet", "greeting":
+				return true
+			}
+			return false
+		},
+	}
+	const want = `count:42 pet:[REDACTED] inner:<host:[REDACTED] > others:<inner:<host:[REDACTED] > > [proto2_test.greeting]:[REDACTED] `
+	if got := tm.Text(m); got != want {
+		t.Errorf("TextMarshaler.Text() with RedactField:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
 func TestMarshalTextNil(t *testing.T) {
 	want := "<nil>"
 	tests := []proto.Message{nil, (*pb2.MyMessage)(nil)}
@@ -874,8 +963,38 @@ var unmarshalTextTests = []UnmarshalTextTest{
 
 	// Bad quoted string
 	{
-		in:  `inner: < host: "\0" >` + "\n",
-		err: `line 1.15: invalid quoted string "\0": \0 requires 2 following digits`,
+		in:  `inner: < host: "\8" >` + "\n",
+		err: `line 1.15: invalid quoted string "\8": unknown escape \8`,
+	},
+
+	// Quoted string with short octal and hex escapes
+	{
+		in: `count: 42 name: "\0\12\x4\x41"`,
+		out: &pb2.MyMessage{
+			Count: proto.Int32(42),
+			Name:  proto.String("\x00\n\x04A"),
+		},
+	},
+
+	// Octal escape out of range
+	{
+		in:  `count: 42 name: "\777"`,
+		err: `line 1.16: invalid quoted string "\777": \777 is not a valid octal escape`,
+	},
+
+	// Quoted string with surrogate pair
+	{
+		in: `count: 42 name: "\uD83D\uDE00"`,
+		out: &pb2.MyMessage{
+			Count: proto.Int32(42),
+			Name:  proto.String("\U0001F600"),
+		},
+	},
+
+	// Unpaired surrogate
+	{
+		in:  `count: 42 name: "\uD83D"`,
+		err: `line 1.16: invalid quoted string "\uD83D": \uD83D is an unpaired surrogate`,
 	},
 
 	// Bad \u escape
@@ -1278,6 +1397,141 @@ func TestUnmarshalText(t *testing.T) {
 	}
 }
 
+func TestUnmarshalTextReader(t *testing.T) {
+	for _, test := range unmarshalTextTests {
+		t.Run("", func(t *testing.T) {
+			want := new(pb2.MyMessage)
+			wantErr := proto.UnmarshalText(test.in, want)
+
+			got := new(pb2.MyMessage)
+			gotErr := proto.UnmarshalTextReader(iotest.OneByteReader(strings.NewReader(test.in)), got)
+			if (gotErr == nil) != (wantErr == nil) || (gotErr != nil && gotErr.Error() != wantErr.Error()) {
+				t.Fatalf("proto.UnmarshalTextReader error mismatch:\ngot:  %v\nwant: %v", gotErr, wantErr)
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("proto.Equal mismatch:\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+
+	// Tokens spanning many reads must be reassembled.
+	long := strings.Repeat("x", 100<<10)
+	m := new(pb2.MyMessage)
+	in := "count: 1\n" + strings.Repeat("pet: \""+long+"\"\n", 3)
+	if err := proto.UnmarshalTextReader(strings.NewReader(in), m); err != nil {
+		t.Fatalf("proto.UnmarshalTextReader error: %v", err)
+	}
+	if len(m.Pet) != 3 || m.Pet[2] != long {
+		t.Errorf("proto.UnmarshalTextReader: got %d pets, want 3 long pets", len(m.Pet))
+	}
+
+	// Read errors are reported as is.
+	errRead := errors.New("read error")
+	r := io.MultiReader(strings.NewReader("count: 1 name: "), iotest.ErrReader(errRead))
+	if err := proto.UnmarshalTextReader(r, new(pb2.MyMessage)); err != errRead {
+		t.Errorf("proto.UnmarshalTextReader error = %v, want %v", err, errRead)
+	}
+
+	// So are read errors after a complete field, which would otherwise
+	// leave a truncated message that parses without error.
+	for _, in := range []string{`count: 1 name: "x" `, `count: 1 name: "x"`, "count: 1 pet: [\"a\", "} {
+		r := io.MultiReader(strings.NewReader(in), iotest.ErrReader(errRead))
+		if err := proto.UnmarshalTextReader(r, new(pb2.MyMessage)); err != errRead {
+			t.Errorf("proto.UnmarshalTextReader(%q followed by a read error) error = %v, want %v", in, err, errRead)
+		}
+	}
+}
+
+func TestUnmarshalTextParseError(t *testing.T) {
+	tests := []struct {
+		in   string
+		m    proto.Message
+		want proto.ParseError
+	}{{
+		in:   "count: 1\nothers {\n  inner <\n    port: \"x\"\n  >\n}",
+		m:    new(pb2.MyMessage),
+		want: proto.ParseError{Line: 4, Column: 11, Offset: 38, Path: "others[0].inner.port"},
+	}, {
+		in:   `count: 1 others {} others { weight: "x" }`,
+		m:    new(pb2.MyMessage),
+		want: proto.ParseError{Line: 1, Column: 37, Offset: 36, Path: "others[1].weight"},
+	}, {
+		in:   `pet: ["a", 2]`,
+		m:    new(pb2.MyMessage),
+		want: proto.ParseError{Line: 1, Column: 12, Offset: 11, Path: "pet[1]"},
+	}, {
+		in:   `str_to_str { key: "env" value: 3 }`,
+		m:    new(pb2.MessageWithMap),
+		want: proto.ParseError{Line: 1, Column: 32, Offset: 31, Path: `str_to_str["env"]`},
+	}, {
+		in:   `str_to_str { value: 3 key: "env" }`,
+		m:    new(pb2.MessageWithMap),
+		want: proto.ParseError{Line: 1, Column: 21, Offset: 20, Path: `str_to_str["env"]`},
+	}, {
+		in:   `msg_mapping { value { f: "x" exact: true } key: 7 }`,
+		m:    new(pb2.MessageWithMap),
+		want: proto.ParseError{Line: 1, Column: 26, Offset: 25, Path: "msg_mapping[7].f"},
+	}, {
+		in:   "msg_mapping { value <\n f: 1; exact: \"x\" >; key: -7 }",
+		m:    new(pb2.MessageWithMap),
+		want: proto.ParseError{Line: 2, Column: 15, Offset: 36, Path: "msg_mapping[-7].exact"},
+	}, {
+		in:   `str_to_str { value: } key: "env"`,
+		m:    new(pb2.MessageWithMap),
+		want: proto.ParseError{Line: 1, Column: 21, Offset: 20, Path: "str_to_str"},
+	}, {
+		in:   `str_to_str { value: 3 }`,
+		m:    new(pb2.MessageWithMap),
+		want: proto.ParseError{Line: 1, Column: 21, Offset: 20, Path: "str_to_str"},
+	}, {
+		in:   `count: 1 [proto2_test.Ext.more] { data: 5 }`,
+		m:    new(pb2.MyMessage),
+		want: proto.ParseError{Line: 1, Column: 41, Offset: 40, Path: "(proto2_test.Ext.more).data"},
+	}, {
+		in:   "count: 1\n\tbogus: 2",
+		m:    new(pb2.MyMessage),
+		want: proto.ParseError{Line: 2, Column: 2, Offset: 10, Path: ""},
+	}}
+
+	for _, tt := range tests {
+		err := proto.UnmarshalText(tt.in, tt.m)
+		var got *proto.ParseError
+		if !errors.As(err, &got) {
+			t.Errorf("proto.UnmarshalText(%q) error = %v, want *proto.ParseError", tt.in, err)
+			continue
+		}
+		got.Message = ""
+		if *got != tt.want {
+			t.Errorf("proto.UnmarshalText(%q) error position:\ngot:  %+v\nwant: %+v", tt.in, *got, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalTextGroupName(t *testing.T) {
+	want := &pb2.MyMessage{Count: proto.Int32(1), Somegroup: &pb2.MyMessage_SomeGroup{GroupField: proto.Int32(5)}}
+	for _, in := range []string{
+		"count: 1 SomeGroup { group_field: 5 }", // by its message type
+		"count: 1 somegroup { group_field: 5 }", // by its field name
+	} {
+		got := new(pb2.MyMessage)
+		if err := proto.UnmarshalText(in, got); err != nil {
+			t.Errorf("proto.UnmarshalText(%q) error: %v", in, err)
+			continue
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("proto.UnmarshalText(%q) = %v, want %v", in, got, want)
+		}
+	}
+	for _, in := range []string{
+		"count: 1 someGroup { group_field: 5 }",
+		"count: 1 SOMEGROUP { group_field: 5 }",
+	} {
+		if err := proto.UnmarshalText(in, new(pb2.MyMessage)); err == nil {
+			t.Errorf("proto.UnmarshalText(%q) succeeded, want error", in)
+		}
+	}
+}
+
 func TestUnmarshalTextCustomMessage(t *testing.T) {
 	msg := &textMessage{}
 	if err := proto.UnmarshalText("custom", msg); err != nil {
@@ -1323,17 +1577,21 @@ func TestMapParsing(t *testing.T) {
 		`msg_mapping:<key:-4, value:<f: 2.0>,>` + // separating commas are okay
 		`msg_mapping<key:-2 value<f: 4.0>>` + // no colon after "value"
 		`msg_mapping:<value:<f: 5.0>>` + // omitted key
+		`msg_mapping:<value:<f: 6.0>; key: 3>` + // value before key
+		`name_mapping:<value:"Queen", key:2>` +
 		`byte_mapping:<key:true value:"so be it">` +
 		`byte_mapping:<>` // omitted key and value
 	want := &pb2.MessageWithMap{
 		NameMapping: map[int32]string{
 			1:    "Beatles",
+			2:    "Queen",
 			1234: "Feist",
 		},
 		MsgMapping: map[int64]*pb2.FloatingPoint{
 			-4: {F: proto.Float64(2.0)},
 			-2: {F: proto.Float64(4.0)},
 			0:  {F: proto.Float64(5.0)},
+			3:  {F: proto.Float64(6.0)},
 		},
 		ByteMapping: map[bool][]byte{
 			false: nil,
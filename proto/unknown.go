@@ -0,0 +1,109 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// UnknownField is a field of a message that was not recognized when it was
+// unmarshaled, such as a field added to a newer version of the message.
+type UnknownField struct {
+	Number   int32
+	WireType int
+
+	// Value is the encoding of the field value, as accepted by
+	// AppendWireField: it excludes the length prefix of WireBytes fields and
+	// the end group marker of WireStartGroup fields.
+	Value []byte
+
+	// Raw is the complete encoding of the field, including its tag.
+	Raw []byte
+}
+
+// UnknownFields returns the unknown fields of m in the order in which
+// they are stored, which is usually the order in which they were unmarshaled.
+// It does not include the unknown fields of nested messages.
+// The Value and Raw fields of the result alias the storage of m.
+//
+// It reports an error if the stored unknown fields are malformed,
+// which can only happen if they were set directly rather than by unmarshaling.
+func UnknownFields(m Message) ([]UnknownField, error) {
+	return findUnknownFields(m, 0)
+}
+
+// GetUnknownField returns the unknown fields of m with the given field number,
+// of which there can be several if it is a repeated field.
+func GetUnknownField(m Message, fieldNum int32) ([]UnknownField, error) {
+	if err := checkFieldNumber(fieldNum); err != nil {
+		return nil, err
+	}
+	return findUnknownFields(m, fieldNum)
+}
+
+// findUnknownFields returns the unknown fields of m with field number num,
+// or all of them if num is zero.
+func findUnknownFields(m Message, num int32) ([]UnknownField, error) {
+	if m == nil {
+		return nil, nil
+	}
+	b := MessageReflect(m).GetUnknown()
+	var fields []UnknownField
+	err := rangeWire(b, func(fnum protowire.Number, i, n int) {
+		if num != 0 && fnum != protowire.Number(num) {
+			return
+		}
+		raw := b[i : i+n : i+n]
+		_, wtyp, tagLen := protowire.ConsumeTag(raw)
+		value := raw[tagLen:]
+		switch wtyp {
+		case protowire.BytesType:
+			value, _ = protowire.ConsumeBytes(value)
+		case protowire.StartGroupType:
+			value, _ = protowire.ConsumeGroup(fnum, value)
+		}
+		fields = append(fields, UnknownField{
+			Number:   int32(fnum),
+			WireType: int(wtyp),
+			Value:    value,
+			Raw:      raw,
+		})
+	})
+	return fields, err
+}
+
+// DeleteUnknownField removes the unknown fields of m with the given
+// field number. It does not modify m if it reports an error.
+func DeleteUnknownField(m Message, fieldNum int32) error {
+	if m == nil {
+		return nil
+	}
+	mr := MessageReflect(m)
+	b := mr.GetUnknown()
+	nb, err := DeleteWireField(b, fieldNum)
+	if err != nil {
+		return err
+	}
+	if len(nb) != len(b) {
+		mr.SetUnknown(nb)
+	}
+	return nil
+}
+
+// HasUnknownFields reports whether m or any message embedded in it,
+// including in extension fields, has unknown fields.
+//
+// This is useful to detect peers that use a newer version of a message
+// than the local one, before the unknown fields are discarded or forwarded.
+func HasUnknownFields(m Message) bool {
+	return m != nil && hasUnknownFields(MessageReflect(m))
+}
+
+func hasUnknownFields(m protoreflect.Message) bool {
+	return !rangeMessages(m, func(m protoreflect.Message) bool {
+		return len(m.GetUnknown()) == 0
+	})
+}
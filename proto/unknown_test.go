@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/testing/protopack"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func TestUnknownFields(t *testing.T) {
+	b := protopack.Message{
+		protopack.Tag{1, protopack.BytesType}, protopack.String("known"),
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(7),
+		protopack.Tag{101, protopack.BytesType}, protopack.String("abc"),
+		protopack.Tag{100, protopack.VarintType}, protopack.Varint(8),
+		protopack.Tag{102, protopack.StartGroupType},
+		protopack.Tag{1, protopack.Fixed32Type}, protopack.Uint32(5),
+		protopack.Tag{102, protopack.EndGroupType},
+	}.Marshal()
+	m := new(pb3.Message)
+	if err := proto.Unmarshal(b, m); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	got, err := proto.UnknownFields(m)
+	if err != nil {
+		t.Fatalf("UnknownFields error: %v", err)
+	}
+	want := []proto.UnknownField{
+		{Number: 100, WireType: proto.WireVarint, Value: []byte{7}, Raw: []byte("\xa0\x06\x07")},
+		{Number: 101, WireType: proto.WireBytes, Value: []byte("abc"), Raw: []byte("\xaa\x06\x03abc")},
+		{Number: 100, WireType: proto.WireVarint, Value: []byte{8}, Raw: []byte("\xa0\x06\x08")},
+		{Number: 102, WireType: proto.WireStartGroup, Value: []byte("\x0d\x05\x00\x00\x00"), Raw: []byte("\xb3\x06\x0d\x05\x00\x00\x00\xb4\x06")},
+	}
+	if !equalUnknownFields(got, want) {
+		t.Errorf("UnknownFields = %v, want %v", got, want)
+	}
+
+	got, err = proto.GetUnknownField(m, 100)
+	if err != nil {
+		t.Fatalf("GetUnknownField error: %v", err)
+	}
+	if !equalUnknownFields(got, []proto.UnknownField{want[0], want[2]}) {
+		t.Errorf("GetUnknownField(100) = %v, want %v", got, []proto.UnknownField{want[0], want[2]})
+	}
+	if got, err := proto.GetUnknownField(m, 1); err != nil || got != nil {
+		t.Errorf("GetUnknownField(1) = %v, %v, want nil", got, err)
+	}
+	if _, err := proto.GetUnknownField(m, 0); err == nil {
+		t.Errorf("GetUnknownField(0) succeeded, want error")
+	}
+
+	if err := proto.DeleteUnknownField(m, 100); err != nil {
+		t.Fatalf("DeleteUnknownField error: %v", err)
+	}
+	if got, _ := proto.UnknownFields(m); !equalUnknownFields(got, []proto.UnknownField{want[1], want[3]}) {
+		t.Errorf("UnknownFields after DeleteUnknownField = %v, want %v", got, []proto.UnknownField{want[1], want[3]})
+	}
+	if m.Name != "known" {
+		t.Errorf("DeleteUnknownField changed known field to %q", m.Name)
+	}
+
+	m.XXX_unrecognized = []byte("\xa0\x06")
+	if _, err := proto.UnknownFields(m); err == nil {
+		t.Errorf("UnknownFields of malformed unknown fields succeeded, want error")
+	}
+	if err := proto.DeleteUnknownField(m, 100); err == nil || !bytes.Equal(m.XXX_unrecognized, []byte("\xa0\x06")) {
+		t.Errorf("DeleteUnknownField of malformed unknown fields = %v, left %q", err, m.XXX_unrecognized)
+	}
+}
+
+func equalUnknownFields(x, y []proto.UnknownField) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i].Number != y[i].Number || x[i].WireType != y[i].WireType ||
+			!bytes.Equal(x[i].Value, y[i].Value) || !bytes.Equal(x[i].Raw, y[i].Raw) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHasUnknownFields(t *testing.T) {
+	withExt := &pb2.MyMessage{Count: proto.Int32(1)}
+	if err := proto.SetExtension(withExt, pb2.E_Ext_More, &pb2.Ext{Data: proto.String("x"), XXX_unrecognized: []byte(rawFields)}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		desc string
+		in   proto.Message
+		want bool
+	}{
+		{"Nil", nil, false},
+		{"NilPtr", (*pb3.Message)(nil), false},
+		{"None", &pb3.Message{Name: "a", Nested: &pb3.Nested{Bunny: "b"}, Children: []*pb3.Message{{}}}, false},
+		{"Top", &pb3.Message{Name: "a", XXX_unrecognized: []byte(rawFields)}, true},
+		{"Nested", &pb3.Message{Nested: &pb3.Nested{XXX_unrecognized: []byte(rawFields)}}, true},
+		{"Slice", &pb3.Message{Children: []*pb3.Message{{}, {XXX_unrecognized: []byte(rawFields)}}}, true},
+		{"Map", &pb3.Message{Terrain: map[string]*pb3.Nested{"a": {}, "b": {XXX_unrecognized: []byte(rawFields)}}}, true},
+		{"Oneof", &pb2.Communique{Union: &pb2.Communique_Msg{Msg: &pb2.Strings{XXX_unrecognized: []byte(rawFields)}}}, true},
+		{"Extension", withExt, true},
+	}
+	for _, tt := range tests {
+		if got := proto.HasUnknownFields(tt.in); got != tt.want {
+			t.Errorf("%s: HasUnknownFields = %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SkipMessage is used as a return value from a WalkFunc to indicate that
+// the fields of the message value being visited are to be skipped.
+// It is not returned as an error by Walk.
+var SkipMessage = errors.New("proto: skip message")
+
+// WalkFunc is the type of the function called by Walk for each field value.
+// If it returns an error other than SkipMessage, Walk stops and returns it.
+type WalkFunc func(v *FieldValue) error
+
+// FieldValue is a field value visited by Walk: the value of a populated
+// singular field, an element of a repeated field, or an entry of a map.
+//
+// A FieldValue is only valid during the call to the WalkFunc.
+type FieldValue struct {
+	// Parent is the message that has the field.
+	Parent protoreflect.Message
+	// Field describes the field, which may be an extension field.
+	Field protoreflect.FieldDescriptor
+	// Index is the index of the element of a repeated field, or -1 otherwise.
+	Index int
+	// MapKey is the key of the entry of a map field, or invalid otherwise.
+	MapKey protoreflect.MapKey
+	// Value is the value of the field, element, or map entry.
+	// Modifications of a message value are visible to the rest of the walk.
+	Value protoreflect.Value
+
+	up      *FieldValue // value of the message that has the field
+	cleared bool
+}
+
+// Path returns the path to the value from the message passed to Walk, such as
+// "inner.host", "others[2].key", or `terrain["north"].bunny`.
+// Extension fields are referred to by their full name in parentheses.
+func (v *FieldValue) Path() string {
+	var chain []*FieldValue
+	for f := v; f != nil; f = f.up {
+		chain = append(chain, f)
+	}
+	var sb strings.Builder
+	for i := len(chain) - 1; i >= 0; i-- {
+		f := chain[i]
+		if i < len(chain)-1 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(fieldPathName(f.Field))
+		switch {
+		case f.Index >= 0:
+			sb.WriteString(indexPathSegment(f.Index))
+		case f.MapKey.IsValid():
+			sb.WriteString(mapKeyPathSegment(f.MapKey, f.Field.MapKey()))
+		}
+	}
+	return sb.String()
+}
+
+// The path helpers below format the segments of a field path, such as
+// a.b[2].(pkg.ext)["key"], as reported by FieldValue.Path,
+// RequiredNotSetError, and ParseError.
+
+// fieldPathName returns the path segment that names fd:
+// its name, or its full name in parentheses if it is an extension.
+func fieldPathName(fd protoreflect.FieldDescriptor) string {
+	if fd.IsExtension() {
+		return "(" + string(fd.FullName()) + ")"
+	}
+	return string(fd.Name())
+}
+
+// indexPathSegment returns the path segment of the list element at index i.
+func indexPathSegment(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// mapKeyPathSegment returns the path segment of the map entry with key k,
+// where keyFD is the key field of the map entry.
+func mapKeyPathSegment(k protoreflect.MapKey, keyFD protoreflect.FieldDescriptor) string {
+	if keyFD.Kind() == protoreflect.StringKind {
+		return "[" + strconv.Quote(k.String()) + "]"
+	}
+	return fmt.Sprintf("[%v]", k.Interface())
+}
+
+// Set replaces the value in the parent message with nv.
+// If nv is a message, Walk visits its fields instead of those of the
+// replaced message.
+func (v *FieldValue) Set(nv protoreflect.Value) {
+	switch {
+	case v.Index >= 0:
+		v.Parent.Mutable(v.Field).List().Set(v.Index, nv)
+	case v.MapKey.IsValid():
+		v.Parent.Mutable(v.Field).Map().Set(v.MapKey, nv)
+	default:
+		v.Parent.Set(v.Field, nv)
+	}
+	v.Value = nv
+}
+
+// Clear removes the value from the parent message, by clearing a singular
+// field, removing an element of a repeated field, or deleting a map entry.
+// Walk does not visit the fields of a cleared message value.
+func (v *FieldValue) Clear() {
+	switch {
+	case v.Index >= 0:
+		list := v.Parent.Mutable(v.Field).List()
+		for i := v.Index; i < list.Len()-1; i++ {
+			list.Set(i, list.Get(i+1))
+		}
+		list.Truncate(list.Len() - 1)
+	case v.MapKey.IsValid():
+		v.Parent.Mutable(v.Field).Map().Clear(v.MapKey)
+	default:
+		v.Parent.Clear(v.Field)
+	}
+	v.cleared = true
+}
+
+// Walk calls fn for each field value in m and all messages embedded in it,
+// in depth-first order: a message value is visited before its fields.
+// Fields are visited in the order in which they are declared, followed by
+// extension fields in order of their field numbers, and map entries are
+// visited in order of their keys.
+//
+// Unpopulated fields are not visited, and neither are unknown fields.
+// The function may modify the value it is called with, through its Set and
+// Clear methods or by modifying a message value directly, but must not
+// otherwise modify the messages that contain it.
+func Walk(m Message, fn WalkFunc) error {
+	if m == nil {
+		return nil
+	}
+	return walkMessage(MessageReflect(m), nil, fn)
+}
+
+func walkMessage(m protoreflect.Message, up *FieldValue, fn WalkFunc) error {
+	if !m.IsValid() {
+		return nil
+	}
+	var fds []protoreflect.FieldDescriptor
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); m.Has(fd) {
+			fds = append(fds, fd)
+		}
+	}
+	n := len(fds)
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fd.IsExtension() {
+			fds = append(fds, fd)
+		}
+		return true
+	})
+	exts := fds[n:]
+	sort.Slice(exts, func(i, j int) bool { return exts[i].Number() < exts[j].Number() })
+
+	fv := &FieldValue{Parent: m, up: up}
+	visit := func() error {
+		switch err := fn(fv); {
+		case err == SkipMessage || err == nil && fv.cleared:
+			return nil
+		case err != nil:
+			return err
+		}
+		if fv.Value.IsValid() && isMessageValue(fv.Field, fv.MapKey.IsValid()) {
+			return walkMessage(fv.Value.Message(), fv, fn)
+		}
+		return nil
+	}
+	for _, fd := range fds {
+		switch {
+		case fd.IsList():
+			list := m.Get(fd).List()
+			for i := 0; i < list.Len(); {
+				*fv = FieldValue{Parent: m, Field: fd, Index: i, Value: list.Get(i), up: up}
+				if err := visit(); err != nil {
+					return err
+				}
+				if !fv.cleared {
+					i++
+				}
+			}
+		case fd.IsMap():
+			for _, e := range sortedMapEntries(fd, m.Get(fd).Map()) {
+				*fv = FieldValue{Parent: m, Field: fd, Index: -1, MapKey: e.key, Value: e.val, up: up}
+				if err := visit(); err != nil {
+					return err
+				}
+			}
+		default:
+			*fv = FieldValue{Parent: m, Field: fd, Index: -1, Value: m.Get(fd), up: up}
+			if err := visit(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rangeMessages calls fn for m and each message embedded in it, including in
+// extension fields, until fn returns false. It reports whether fn returned
+// true for all messages. Unlike Walk, it visits messages in no particular
+// order and does not visit scalar values, so internal callers that only
+// operate on messages use it to avoid the cost of Walk.
+func rangeMessages(m protoreflect.Message, fn func(protoreflect.Message) bool) bool {
+	if !fn(m) {
+		return false
+	}
+	ok := true
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := v.List()
+				for i := 0; i < list.Len() && ok; i++ {
+					ok = rangeMessages(list.Get(i).Message(), fn)
+				}
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					ok = rangeMessages(v.Message(), fn)
+					return ok
+				})
+			}
+		case fd.Message() != nil:
+			ok = rangeMessages(v.Message(), fn)
+		}
+		return ok
+	})
+	return ok
+}
+
+// isMessageValue reports whether the values of fd are messages,
+// where isMapValue reports whether the value is that of a map entry.
+func isMessageValue(fd protoreflect.FieldDescriptor, isMapValue bool) bool {
+	if isMapValue {
+		return fd.MapValue().Message() != nil
+	}
+	return fd.Message() != nil
+}
+
+type mapEntry struct {
+	key protoreflect.MapKey
+	val protoreflect.Value
+}
+
+// sortedMapEntries returns the entries of the map field fd in order of
+// their keys.
+func sortedMapEntries(fd protoreflect.FieldDescriptor, mv protoreflect.Map) []mapEntry {
+	var entries []mapEntry
+	mv.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		entries = append(entries, mapEntry{k, v})
+		return true
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		switch fd.MapKey().Kind() {
+		case protoreflect.BoolKind:
+			return !entries[i].key.Bool() && entries[j].key.Bool()
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			return entries[i].key.Int() < entries[j].key.Int()
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			return entries[i].key.Uint() < entries[j].key.Uint()
+		default:
+			return entries[i].key.String() < entries[j].key.String()
+		}
+	})
+	return entries
+}
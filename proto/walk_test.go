@@ -0,0 +1,233 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protorand"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+func walkPaths(t *testing.T, m proto.Message, fn proto.WalkFunc) []string {
+	var paths []string
+	err := proto.Walk(m, func(v *proto.FieldValue) error {
+		paths = append(paths, v.Path())
+		if fn != nil {
+			return fn(v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk error: %v", err)
+	}
+	return paths
+}
+
+func TestWalk(t *testing.T) {
+	m2 := &pb2.MyMessage{
+		Count:  proto.Int32(42),
+		Name:   proto.String("name"),
+		Pet:    []string{"horsey", "bunny"},
+		Inner:  &pb2.InnerMessage{Host: proto.String("h"), Port: proto.Int32(1)},
+		Others: []*pb2.OtherMessage{{Key: proto.Int64(1)}, {Inner: &pb2.InnerMessage{Host: proto.String("a")}}},
+	}
+	if err := proto.SetExtension(m2, pb2.E_Greeting, []string{"hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := proto.SetExtension(m2, pb2.E_Ext_More, &pb2.Ext{Data: proto.String("ext")}); err != nil {
+		t.Fatal(err)
+	}
+	m3 := &pb3.Message{
+		Name:     "name",
+		Terrain:  map[string]*pb3.Nested{"b": {Bunny: "x"}, "a": {Cute: true}},
+		Children: []*pb3.Message{{}, {Key: []uint64{1}}},
+	}
+
+	tests := []struct {
+		in   proto.Message
+		want []string
+	}{{
+		in:   nil,
+		want: nil,
+	}, {
+		in: m2,
+		want: []string{
+			"count",
+			"name",
+			"pet[0]",
+			"pet[1]",
+			"inner",
+			"inner.host",
+			"inner.port",
+			"others[0]",
+			"others[0].key",
+			"others[1]",
+			"others[1].inner",
+			"others[1].inner.host",
+			"(proto2_test.Ext.more)",
+			"(proto2_test.Ext.more).data",
+			"(proto2_test.greeting)[0]",
+		},
+	}, {
+		in: m3,
+		want: []string{
+			"name",
+			`terrain["a"]`,
+			`terrain["a"].cute`,
+			`terrain["b"]`,
+			`terrain["b"].bunny`,
+			"children[0]",
+			"children[1]",
+			"children[1].key[0]",
+		},
+	}}
+	for _, tt := range tests {
+		got := walkPaths(t, tt.in, nil)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Walk(%v) paths:\ngot:  %q\nwant: %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWalkSkipMessage(t *testing.T) {
+	m := &pb2.MyMessage{
+		Count:  proto.Int32(42),
+		Inner:  &pb2.InnerMessage{Host: proto.String("h")},
+		Others: []*pb2.OtherMessage{{Key: proto.Int64(1)}, {Key: proto.Int64(2)}},
+	}
+	got := walkPaths(t, m, func(v *proto.FieldValue) error {
+		if v.Field.Name() == "inner" || v.Index == 0 {
+			return proto.SkipMessage
+		}
+		return nil
+	})
+	want := []string{"count", "inner", "others[0]", "others[1]", "others[1].key"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk paths:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestWalkMutate(t *testing.T) {
+	m := &pb3.Message{
+		Name:     "secret",
+		Key:      []uint64{1, 2, 2, 3},
+		Terrain:  map[string]*pb3.Nested{"a": {Bunny: "x"}, "b": {Bunny: "secret"}},
+		Children: []*pb3.Message{{Name: "secret"}, {Name: "drop"}, {Name: "keep"}},
+		Nested:   &pb3.Nested{Bunny: "old"},
+	}
+	got := walkPaths(t, m, func(v *proto.FieldValue) error {
+		switch {
+		case v.Field.Kind() == protoreflect.StringKind && v.Value.String() == "secret":
+			v.Set(protoreflect.ValueOfString("REDACTED"))
+		case v.Field.Name() == "key" && v.Value.Uint() == 2:
+			v.Clear()
+		case v.Field.Name() == "terrain" && v.MapKey.String() == "a":
+			v.Clear()
+		case v.Field.Name() == "children" && v.Value.Message().Get(v.Field.Message().Fields().ByName("name")).String() == "drop":
+			v.Clear()
+		case v.Field.Name() == "nested":
+			v.Set(protoreflect.ValueOfMessage(proto.MessageReflect(&pb3.Nested{Bunny: "new", Cute: true})))
+		}
+		return nil
+	})
+	want := &pb3.Message{
+		Name:     "REDACTED",
+		Key:      []uint64{1, 3},
+		Terrain:  map[string]*pb3.Nested{"b": {Bunny: "REDACTED"}},
+		Children: []*pb3.Message{{Name: "REDACTED"}, {Name: "keep"}},
+		Nested:   &pb3.Nested{Bunny: "new", Cute: true},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("Walk mutation mismatch:\ngot:  %v\nwant: %v", m, want)
+	}
+	wantPaths := []string{
+		"name",
+		"key[0]", "key[1]", "key[1]", "key[1]",
+		"nested", "nested.bunny", "nested.cute",
+		`terrain["a"]`, `terrain["b"]`, `terrain["b"].bunny`,
+		"children[0]", "children[0].name", "children[1]", "children[1]", "children[1].name",
+	}
+	if !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("Walk paths:\ngot:  %q\nwant: %q", got, wantPaths)
+	}
+}
+
+func TestWalkError(t *testing.T) {
+	m := &pb3.Message{Name: "a", Children: []*pb3.Message{{Name: "b"}, {Name: "c"}}}
+	stop := errors.New("stop")
+	var n int
+	err := proto.Walk(m, func(v *proto.FieldValue) error {
+		n++
+		if v.Path() == "children[0].name" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("Walk error = %v, want %v", err, stop)
+	}
+	if n != 3 {
+		t.Errorf("Walk visited %d values before stopping, want 3", n)
+	}
+}
+
+// benchmarkWalkMessages is a large message with nested messages, lists,
+// and maps for benchmarking the functions that traverse messages.
+func benchmarkWalkMessages() []proto.Message {
+	g := protorand.New(1)
+	var ms []proto.Message
+	for _, m := range []proto.Message{new(pb2.MyMessage), new(pb2.MessageWithMap), new(pb3.Message)} {
+		g.Fill(m)
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+func BenchmarkWalk(b *testing.B) {
+	ms := benchmarkWalkMessages()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, m := range ms {
+			proto.Walk(m, func(*proto.FieldValue) error { return nil })
+		}
+	}
+}
+
+func BenchmarkDiscardUnknown(b *testing.B) {
+	ms := benchmarkWalkMessages()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, m := range ms {
+			proto.DiscardUnknown(m)
+		}
+	}
+}
+
+func BenchmarkSetDefaults(b *testing.B) {
+	ms := benchmarkWalkMessages()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, m := range ms {
+			proto.SetDefaults(m)
+		}
+	}
+}
+
+func BenchmarkHasUnknownFields(b *testing.B) {
+	ms := benchmarkWalkMessages()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, m := range ms {
+			proto.HasUnknownFields(m)
+		}
+	}
+}
@@ -27,6 +27,19 @@ func Marshal(m Message) ([]byte, error) {
 	return b, err
 }
 
+// MarshalDeterministic returns the wire-format encoding of m using
+// deterministic serialization, as described for Buffer.SetDeterministic.
+//
+// Deterministic serialization is only stable for a given binary.
+// Use MarshalCanonical for an encoding that is stable across versions.
+func MarshalDeterministic(m Message) ([]byte, error) {
+	b, err := marshalAppend(nil, m, true)
+	if b == nil {
+		b = zeroBytes
+	}
+	return b, err
+}
+
 var zeroBytes = make([]byte, 0, 0)
 
 func marshalAppend(buf []byte, m Message, deterministic bool) ([]byte, error) {
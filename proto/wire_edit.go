@@ -0,0 +1,162 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The functions in this file edit wire-format messages without unmarshaling
+// them. They rely on the fact that parsing the concatenation of two encodings
+// is equivalent to parsing each of them and merging the results: the last
+// value of a singular scalar field wins, singular message fields are merged,
+// and the elements of repeated fields are appended.
+//
+// All of them check that their inputs are well-formed on the wire,
+// so that a malformed input cannot corrupt the fields that follow it,
+// but they do not check the fields against any message type.
+// The exception is the message that AppendWireField appends to,
+// so that building a message with repeated calls takes linear time.
+
+// AppendWireField appends a field with the given field number, wire type,
+// and value to the wire-format message b.
+//
+// The value is the encoding of the field value that follows the tag,
+// except that for WireBytes it excludes the length prefix,
+// and for WireStartGroup it excludes the end group marker.
+// For example, the value of a WireBytes field of a message type is the
+// output of Marshal for that message, and the value of a WireVarint field
+// is the output of EncodeVarint.
+//
+// Only the value is checked to be well-formed; b is assumed to be
+// a well-formed message, such as the output of Marshal or of the other
+// functions in this file.
+func AppendWireField(b []byte, fieldNum int32, wireType int, value []byte) ([]byte, error) {
+	if err := checkFieldNumber(fieldNum); err != nil {
+		return b, err
+	}
+	num := protowire.Number(fieldNum)
+	switch wireType {
+	case WireVarint:
+		if _, n := protowire.ConsumeVarint(value); n != len(value) {
+			return b, fmt.Errorf("proto: invalid varint value %x", value)
+		}
+	case WireFixed32:
+		if len(value) != 4 {
+			return b, fmt.Errorf("proto: invalid fixed32 value of length %d", len(value))
+		}
+	case WireFixed64:
+		if len(value) != 8 {
+			return b, fmt.Errorf("proto: invalid fixed64 value of length %d", len(value))
+		}
+	case WireBytes:
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendBytes(b, value), nil
+	case WireStartGroup:
+		if err := checkWire(value); err != nil {
+			return b, err
+		}
+		b = protowire.AppendTag(b, num, protowire.StartGroupType)
+		b = append(b, value...)
+		return protowire.AppendTag(b, num, protowire.EndGroupType), nil
+	default:
+		return b, fmt.Errorf("proto: invalid wire type %d", wireType)
+	}
+	b = protowire.AppendTag(b, num, protowire.Type(wireType))
+	return append(b, value...), nil
+}
+
+// ReplaceWireField returns a copy of the wire-format message b where all
+// top-level fields with the given field number are removed, and a single
+// field with the given wire type and value, as accepted by AppendWireField,
+// is appended. Since the new field comes last, it takes precedence over
+// any other member of the same oneof in b.
+//
+// To set several elements of a repeated field, use DeleteWireField
+// followed by AppendWireField for each element.
+func ReplaceWireField(b []byte, fieldNum int32, wireType int, value []byte) ([]byte, error) {
+	field, err := AppendWireField(nil, fieldNum, wireType, value)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(b)+len(field))
+	last := 0 // start of the bytes of b not yet copied to out
+	err = rangeWire(b, func(num protowire.Number, i, n int) {
+		if num != protowire.Number(fieldNum) {
+			return
+		}
+		out = append(out, b[last:i]...)
+		last = i + n
+	})
+	if err != nil {
+		return nil, err
+	}
+	out = append(out, b[last:]...)
+	return append(out, field...), nil
+}
+
+// DeleteWireField returns the wire-format message b without any of its
+// top-level fields with the given field number.
+// The result is b itself if it has no such field, and a copy otherwise.
+func DeleteWireField(b []byte, fieldNum int32) ([]byte, error) {
+	if err := checkFieldNumber(fieldNum); err != nil {
+		return b, err
+	}
+	var out []byte
+	last := 0 // start of the bytes of b not yet copied to out
+	err := rangeWire(b, func(num protowire.Number, i, n int) {
+		if num != protowire.Number(fieldNum) {
+			return
+		}
+		if out == nil {
+			out = make([]byte, 0, len(b)-n)
+		}
+		out = append(out, b[last:i]...)
+		last = i + n
+	})
+	if err != nil {
+		return b, err
+	}
+	if out == nil {
+		return b, nil
+	}
+	return append(out, b[last:]...), nil
+}
+
+// MergeWire appends the wire-format message src to the wire-format message
+// dst, which is equivalent to unmarshaling both into the same message
+// (as Merge does for unmarshaled messages) and marshaling the result.
+// Like append, it may reuse the storage of dst.
+func MergeWire(dst, src []byte) ([]byte, error) {
+	if err := checkWire(dst); err != nil {
+		return dst, err
+	}
+	if err := checkWire(src); err != nil {
+		return dst, err
+	}
+	return append(dst, src...), nil
+}
+
+// checkWire reports an error if b is not a well-formed wire-format message.
+func checkWire(b []byte) error {
+	return rangeWire(b, func(protowire.Number, int, int) {})
+}
+
+// rangeWire calls f with the field number, offset, and length of each
+// top-level field of the wire-format message b,
+// and reports an error if b is malformed.
+func rangeWire(b []byte, f func(num protowire.Number, i, n int)) error {
+	for i := 0; i < len(b); {
+		num, _, n := protowire.ConsumeField(b[i:])
+		if n < 0 {
+			return wireErrorf(i, "%v", protowire.ParseError(n))
+		}
+		f(num, i, n)
+		i += n
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+)
+
+func TestWireEdit(t *testing.T) {
+	base := &pb2.MyMessage{
+		Count: proto.Int32(1),
+		Name:  proto.String("base"),
+		Pet:   []string{"a", "b"},
+		Inner: &pb2.InnerMessage{Host: proto.String("h"), Port: proto.Int32(80)},
+	}
+	b, err := proto.Marshal(base)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	inner, err := proto.Marshal(&pb2.InnerMessage{Host: proto.String("h2"), Connected: proto.Bool(true)})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	group, err := proto.Marshal(&pb2.MyMessage_SomeGroup{GroupField: proto.Int32(9)})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	tests := []struct {
+		desc string
+		edit func([]byte) ([]byte, error)
+		want *pb2.MyMessage
+	}{{
+		desc: "append scalar",
+		edit: func(b []byte) ([]byte, error) {
+			return proto.AppendWireField(b, 1, proto.WireVarint, proto.EncodeVarint(5))
+		},
+		want: &pb2.MyMessage{Count: proto.Int32(5), Name: base.Name, Pet: base.Pet, Inner: base.Inner},
+	}, {
+		desc: "append repeated",
+		edit: func(b []byte) ([]byte, error) { return proto.AppendWireField(b, 4, proto.WireBytes, []byte("c")) },
+		want: &pb2.MyMessage{Count: base.Count, Name: base.Name, Pet: []string{"a", "b", "c"}, Inner: base.Inner},
+	}, {
+		desc: "append message merges",
+		edit: func(b []byte) ([]byte, error) { return proto.AppendWireField(b, 5, proto.WireBytes, inner) },
+		want: &pb2.MyMessage{Count: base.Count, Name: base.Name, Pet: base.Pet, Inner: &pb2.InnerMessage{Host: proto.String("h2"), Port: proto.Int32(80), Connected: proto.Bool(true)}},
+	}, {
+		desc: "append group",
+		edit: func(b []byte) ([]byte, error) { return proto.AppendWireField(b, 8, proto.WireStartGroup, group) },
+		want: &pb2.MyMessage{Count: base.Count, Name: base.Name, Pet: base.Pet, Inner: base.Inner, Somegroup: &pb2.MyMessage_SomeGroup{GroupField: proto.Int32(9)}},
+	}, {
+		desc: "replace message",
+		edit: func(b []byte) ([]byte, error) { return proto.ReplaceWireField(b, 5, proto.WireBytes, inner) },
+		want: &pb2.MyMessage{Count: base.Count, Name: base.Name, Pet: base.Pet, Inner: &pb2.InnerMessage{Host: proto.String("h2"), Connected: proto.Bool(true)}},
+	}, {
+		desc: "replace repeated",
+		edit: func(b []byte) ([]byte, error) { return proto.ReplaceWireField(b, 4, proto.WireBytes, []byte("c")) },
+		want: &pb2.MyMessage{Count: base.Count, Name: base.Name, Pet: []string{"c"}, Inner: base.Inner},
+	}, {
+		desc: "replace absent",
+		edit: func(b []byte) ([]byte, error) { return proto.ReplaceWireField(b, 3, proto.WireBytes, []byte("q")) },
+		want: &pb2.MyMessage{Count: base.Count, Name: base.Name, Quote: proto.String("q"), Pet: base.Pet, Inner: base.Inner},
+	}, {
+		desc: "delete repeated",
+		edit: func(b []byte) ([]byte, error) { return proto.DeleteWireField(b, 4) },
+		want: &pb2.MyMessage{Count: base.Count, Name: base.Name, Inner: base.Inner},
+	}, {
+		desc: "delete absent",
+		edit: func(b []byte) ([]byte, error) { return proto.DeleteWireField(b, 3) },
+		want: base,
+	}, {
+		desc: "merge",
+		edit: func(b []byte) ([]byte, error) {
+			src, err := proto.Marshal(&pb2.MyMessage{Count: proto.Int32(2), Pet: []string{"c"}, Inner: &pb2.InnerMessage{Host: proto.String("h2")}})
+			if err != nil {
+				return nil, err
+			}
+			return proto.MergeWire(b, src)
+		},
+		want: &pb2.MyMessage{Count: proto.Int32(2), Name: base.Name, Pet: []string{"a", "b", "c"}, Inner: &pb2.InnerMessage{Host: proto.String("h2"), Port: proto.Int32(80)}},
+	}}
+	for _, tt := range tests {
+		orig := append([]byte(nil), b...)
+		in := b[:len(b):len(b)]
+		out, err := tt.edit(in)
+		if err != nil {
+			t.Errorf("%s: error: %v", tt.desc, err)
+			continue
+		}
+		if !bytes.Equal(b, orig) {
+			t.Errorf("%s: modified input", tt.desc)
+		}
+		got := new(pb2.MyMessage)
+		if err := proto.Unmarshal(out, got); err != nil {
+			t.Errorf("%s: Unmarshal error: %v", tt.desc, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("%s: mismatch:\ngot:  %v\nwant: %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestWireEditOrder(t *testing.T) {
+	// count: 1, pet: "a", name: "n", pet: "b"
+	b := []byte("\x08\x01\x22\x01a\x12\x01n\x22\x01b")
+	got, err := proto.ReplaceWireField(b, 4, proto.WireBytes, []byte("c"))
+	if err != nil {
+		t.Fatalf("ReplaceWireField error: %v", err)
+	}
+	if want := []byte("\x08\x01\x12\x01n\x22\x01c"); !bytes.Equal(got, want) {
+		t.Errorf("ReplaceWireField = %q, want %q", got, want)
+	}
+	got, err = proto.DeleteWireField(b, 4)
+	if err != nil {
+		t.Fatalf("DeleteWireField error: %v", err)
+	}
+	if want := []byte("\x08\x01\x12\x01n"); !bytes.Equal(got, want) {
+		t.Errorf("DeleteWireField = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceWireFieldOneof(t *testing.T) {
+	b, err := proto.Marshal(&pb2.Communique{Union: &pb2.Communique_Number{Number: 1}})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	// Set name, then number again; the last member set must win.
+	b, err = proto.ReplaceWireField(b, 6, proto.WireBytes, []byte("n"))
+	if err != nil {
+		t.Fatalf("ReplaceWireField error: %v", err)
+	}
+	b, err = proto.ReplaceWireField(b, 5, proto.WireVarint, proto.EncodeVarint(2))
+	if err != nil {
+		t.Fatalf("ReplaceWireField error: %v", err)
+	}
+	got := new(pb2.Communique)
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if want := (&pb2.Communique{Union: &pb2.Communique_Number{Number: 2}}); !proto.Equal(got, want) {
+		t.Errorf("ReplaceWireField result = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkAppendWireField(b *testing.B) {
+	value := []byte("element")
+	for i := 0; i < b.N; i++ {
+		var m []byte
+		for j := 0; j < 1000; j++ {
+			var err error
+			if m, err = proto.AppendWireField(m, 4, proto.WireBytes, value); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestWireEditErrors(t *testing.T) {
+	valid := []byte("\x08\x01")
+	truncated := []byte("\x12\x05n")
+	for _, tt := range []struct {
+		desc string
+		err  error
+		want string
+	}{
+		{"invalid field number", second(proto.AppendWireField(valid, 0, proto.WireVarint, []byte{1})), "invalid field number 0"},
+		{"invalid wire type", second(proto.AppendWireField(valid, 1, proto.WireEndGroup, nil)), "invalid wire type 4"},
+		{"invalid varint", second(proto.AppendWireField(valid, 1, proto.WireVarint, []byte{0x80})), "invalid varint"},
+		{"trailing varint bytes", second(proto.AppendWireField(valid, 1, proto.WireVarint, []byte{1, 2})), "invalid varint"},
+		{"invalid fixed32", second(proto.AppendWireField(valid, 1, proto.WireFixed32, []byte{1})), "invalid fixed32"},
+		{"invalid fixed64", second(proto.AppendWireField(valid, 1, proto.WireFixed64, []byte{1})), "invalid fixed64"},
+		{"invalid group", second(proto.AppendWireField(valid, 1, proto.WireStartGroup, truncated)), "offset 0"},
+		{"replace in malformed", second(proto.ReplaceWireField(append(valid, truncated...), 1, proto.WireVarint, []byte{1})), "offset 2"},
+		{"delete in malformed", second(proto.DeleteWireField(append(valid, truncated...), 1)), "offset 2"},
+		{"delete invalid field number", second(proto.DeleteWireField(valid, -1)), "invalid field number -1"},
+		{"merge malformed dst", second(proto.MergeWire(truncated, valid)), "offset 0"},
+		{"merge malformed src", second(proto.MergeWire(valid, truncated)), "offset 0"},
+		{"merge unmatched end group", second(proto.MergeWire(valid, []byte("\x0c"))), "offset 0"},
+	} {
+		if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.desc, tt.err, tt.want)
+		}
+	}
+}
+
+func second(_ []byte, err error) error { return err }
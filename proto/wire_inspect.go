@@ -0,0 +1,279 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// WireKind is the interpretation of a field value guessed by DecodeWire.
+type WireKind int
+
+const (
+	// WireKindScalar is the kind of WireVarint, WireFixed32, and WireFixed64 fields.
+	WireKindScalar WireKind = iota
+	// WireKindBytes is the kind of WireBytes fields with no better interpretation.
+	WireKindBytes
+	// WireKindString is the kind of WireBytes fields that hold printable UTF-8 text.
+	WireKindString
+	// WireKindMessage is the kind of WireStartGroup fields and
+	// WireBytes fields that hold a valid wire-format message.
+	WireKindMessage
+	// WireKindPacked is the kind of WireBytes fields that hold
+	// a packed list of varints.
+	WireKindPacked
+)
+
+func (k WireKind) String() string {
+	switch k {
+	case WireKindScalar:
+		return "scalar"
+	case WireKindBytes:
+		return "bytes"
+	case WireKindString:
+		return "string"
+	case WireKindMessage:
+		return "message"
+	case WireKindPacked:
+		return "packed"
+	default:
+		return fmt.Sprintf("WireKind(%d)", int(k))
+	}
+}
+
+// WireField is a field decoded from the wire format without a schema.
+type WireField struct {
+	Number   int32    // field number
+	WireType int      // wire type (e.g., WireBytes)
+	Offset   int      // offset of the field tag in the input
+	Kind     WireKind // guessed interpretation of the value
+
+	Scalar uint64      // value of a WireKindScalar field
+	Bytes  []byte      // value of a WireBytes field, excluding the length prefix
+	Fields WireMessage // fields of a WireKindMessage field
+	Packed []uint64    // values of a WireKindPacked field
+}
+
+// WireMessage is a message decoded from the wire format without a schema.
+//
+// Its String method renders it in a format similar to the text format,
+// where fields are identified by number. Its JSON encoding is an array of
+// objects, each holding the number, wireType, offset, kind and value of
+// a field.
+type WireMessage []*WireField
+
+// maxWireDepth is the maximum nesting depth of messages decoded by DecodeWire.
+const maxWireDepth = 100
+
+// DecodeWire parses b as a wire-format message without a schema.
+//
+// Since the wire format does not distinguish between bytes, strings,
+// nested messages, and packed repeated fields, the Kind of each WireBytes
+// field is a guess: printable UTF-8 text is a string; otherwise, valid
+// wire-format data is a message, and a sequence of varints is a packed list.
+//
+// If b is malformed, DecodeWire returns the fields decoded before the error,
+// and an error reporting the offset of the malformed field.
+func DecodeWire(b []byte) (WireMessage, error) {
+	m, _, err := decodeWire(b, 0, 0, 0)
+	return m, err
+}
+
+// decodeWire parses fields from b until its end, or until the end group
+// marker for field endNum if it is non-zero. The offset of b in the input
+// is base. It returns the number of bytes consumed.
+func decodeWire(b []byte, base int, endNum protowire.Number, depth int) (WireMessage, int, error) {
+	m := WireMessage{}
+	var i int
+	for i < len(b) {
+		num, wtyp, n := protowire.ConsumeTag(b[i:])
+		if n < 0 {
+			return m, i, wireErrorf(base+i, "%v", protowire.ParseError(n))
+		}
+		f := &WireField{Number: int32(num), WireType: int(wtyp), Offset: base + i}
+		i += n
+
+		switch wtyp {
+		case protowire.VarintType:
+			f.Scalar, n = protowire.ConsumeVarint(b[i:])
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b[i:])
+			f.Scalar = uint64(v)
+		case protowire.Fixed64Type:
+			f.Scalar, n = protowire.ConsumeFixed64(b[i:])
+		case protowire.BytesType:
+			f.Bytes, n = protowire.ConsumeBytes(b[i:])
+			if n >= 0 {
+				interpretWireBytes(f, base+i+n-len(f.Bytes), depth)
+			}
+		case protowire.StartGroupType:
+			if depth >= maxWireDepth {
+				return m, i, wireErrorf(f.Offset, "exceeded maximum nesting depth")
+			}
+			var err error
+			f.Kind = WireKindMessage
+			f.Fields, n, err = decodeWire(b[i:], base+i, num, depth+1)
+			if err != nil {
+				return append(m, f), i + n, err
+			}
+		case protowire.EndGroupType:
+			if num != endNum {
+				return m, i, wireErrorf(f.Offset, "unexpected end group marker for field %d", num)
+			}
+			return m, i, nil
+		default:
+			return m, i, wireErrorf(f.Offset, "cannot parse reserved wire type")
+		}
+		if n < 0 {
+			return m, i, wireErrorf(f.Offset, "%v", protowire.ParseError(n))
+		}
+		i += n
+		m = append(m, f)
+	}
+	if endNum != 0 {
+		return m, i, wireErrorf(base+i, "missing end group marker for field %d", endNum)
+	}
+	return m, i, nil
+}
+
+func wireErrorf(offset int, format string, args ...interface{}) error {
+	// Avoid repeating the prefix of errors from the protowire package.
+	msg := strings.TrimPrefix(fmt.Sprintf(format, args...), "proto:")
+	msg = strings.TrimLeftFunc(msg, unicode.IsSpace)
+	return fmt.Errorf("proto: offset %d: %s", offset, msg)
+}
+
+// interpretWireBytes guesses the kind of the WireBytes field f,
+// where base is the offset of its value in the input.
+func interpretWireBytes(f *WireField, base, depth int) {
+	b := f.Bytes
+	if len(b) == 0 || isPrintableText(b) {
+		f.Kind = WireKindString
+		return
+	}
+	if depth < maxWireDepth {
+		if m, _, err := decodeWire(b, base, 0, depth+1); err == nil {
+			f.Kind = WireKindMessage
+			f.Fields = m
+			return
+		}
+	}
+	var vs []uint64
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 || n != protowire.SizeVarint(v) {
+			f.Kind = WireKindBytes
+			return
+		}
+		vs = append(vs, v)
+		b = b[n:]
+	}
+	f.Kind = WireKindPacked
+	f.Packed = vs
+}
+
+// isPrintableText reports whether b is UTF-8 text made of printable
+// characters and common whitespace.
+func isPrintableText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+func wireTypeName(wtyp int) string {
+	switch wtyp {
+	case WireVarint:
+		return "varint"
+	case WireFixed32:
+		return "fixed32"
+	case WireFixed64:
+		return "fixed64"
+	case WireBytes:
+		return "bytes"
+	case WireStartGroup:
+		return "group"
+	default:
+		return fmt.Sprintf("wiretype%d", wtyp)
+	}
+}
+
+// String returns a text representation of m.
+func (m WireMessage) String() string {
+	w := &textWriter{complete: true}
+	w.writeWireMessage(m)
+	return string(w.buf)
+}
+
+func (w *textWriter) writeWireMessage(m WireMessage) {
+	for _, f := range m {
+		fmt.Fprint(w, f.Number)
+		switch f.Kind {
+		case WireKindMessage:
+			if f.WireType == WireStartGroup {
+				w.Write([]byte(" /* group */"))
+			}
+			w.Write([]byte(" {\n"))
+			w.indent++
+			w.writeWireMessage(f.Fields)
+			w.indent--
+			w.Write(endBraceNewline)
+			continue
+		case WireKindScalar:
+			fmt.Fprintf(w, ": %d", f.Scalar)
+			if f.WireType != WireVarint {
+				fmt.Fprintf(w, " /* %s */", wireTypeName(f.WireType))
+			}
+		case WireKindPacked:
+			w.Write([]byte(": ["))
+			for i, v := range f.Packed {
+				if i > 0 {
+					w.Write([]byte(", "))
+				}
+				fmt.Fprint(w, v)
+			}
+			w.Write([]byte("] /* packed */"))
+		default:
+			fmt.Fprintf(w, ": %q", f.Bytes)
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f *WireField) MarshalJSON() ([]byte, error) {
+	var v interface{}
+	switch f.Kind {
+	case WireKindScalar:
+		v = f.Scalar
+	case WireKindString:
+		v = string(f.Bytes)
+	case WireKindMessage:
+		v = f.Fields
+	case WireKindPacked:
+		v = f.Packed
+	default:
+		v = f.Bytes
+	}
+	return json.Marshal(struct {
+		Number   int32       `json:"number"`
+		WireType string      `json:"wireType"`
+		Offset   int         `json:"offset"`
+		Kind     string      `json:"kind"`
+		Value    interface{} `json:"value"`
+	}{f.Number, wireTypeName(f.WireType), f.Offset, f.Kind.String(), v})
+}
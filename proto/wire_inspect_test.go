@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+)
+
+func TestDecodeWire(t *testing.T) {
+	b, err := proto.Marshal(&pb2.MyMessage{
+		Count:    proto.Int32(42),
+		Name:     proto.String("Dave"),
+		Quote:    proto.String(""),
+		Pet:      []string{"bunny"},
+		Bikeshed: pb2.MyMessage_BLUE.Enum(),
+		Inner:    &pb2.InnerMessage{Host: proto.String("footrest.syd"), Port: proto.Int32(7001)},
+		RepBytes: [][]byte{{0xff, 0xff}},
+		Somegroup: &pb2.MyMessage_SomeGroup{
+			GroupField: proto.Int32(8),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	o := proto.NewBuffer(b)
+	o.SetBuf(append(o.Bytes(), "\x4a\x03\x01\x96\x01"...)) // field 9: packed [1, 150]
+	o.EncodeTag(10, proto.WireFixed32)
+	o.EncodeFixed32(7)
+
+	m, err := proto.DecodeWire(o.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeWire error: %v", err)
+	}
+	const want = `1: 42
+2: "Dave"
+3: ""
+4: "bunny"
+5 {
+  1: "footrest.syd"
+  2: 7001
+}
+7: 2
+8 /* group */ {
+  9: 8
+}
+10: "\xff\xff"
+9: [1, 150] /* packed */
+10: 7 /* fixed32 */
+`
+	if got := m.String(); got != want {
+		t.Errorf("DecodeWire(...).String():\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	if m[0].Offset != 0 || m[1].Offset != 2 {
+		t.Errorf("offsets = %d, %d; want 0, 2", m[0].Offset, m[1].Offset)
+	}
+	if inner := m[4].Fields; len(inner) != 2 || inner[1].Offset != m[4].Offset+16 {
+		t.Errorf("nested field offsets = %v, want second at %d", inner, m[4].Offset+16)
+	}
+
+	got, err := json.Marshal(m[4:6])
+	if err != nil {
+		t.Fatalf("json.Marshal error: %v", err)
+	}
+	const wantJSON = `[{"number":5,"wireType":"bytes","offset":17,"kind":"message","value":[` +
+		`{"number":1,"wireType":"bytes","offset":19,"kind":"string","value":"footrest.syd"},` +
+		`{"number":2,"wireType":"varint","offset":33,"kind":"scalar","value":7001}]},` +
+		`{"number":7,"wireType":"varint","offset":36,"kind":"scalar","value":2}]`
+	if string(got) != wantJSON {
+		t.Errorf("json.Marshal(DecodeWire(...)):\ngot:  %s\nwant: %s", got, wantJSON)
+	}
+}
+
+func TestDecodeWireErrors(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantLen int
+		wantErr string
+	}{
+		{in: "\x08\x01\x12\x05ab", wantLen: 1, wantErr: "offset 2: unexpected EOF"},
+		{in: "\x08\x01\x00", wantLen: 1, wantErr: "offset 2: invalid field number"},
+		{in: "\x0b\x08\x01", wantLen: 1, wantErr: "offset 3: missing end group marker for field 1"},
+		{in: "\x0b\x14", wantLen: 1, wantErr: "offset 1: unexpected end group marker for field 2"},
+		{in: "\x0c", wantLen: 0, wantErr: "offset 0: unexpected end group marker for field 1"},
+		{in: "\x0f", wantLen: 0, wantErr: "offset 0: cannot parse reserved wire type"},
+		{in: strings.Repeat("\x0b", 200), wantLen: 1, wantErr: "exceeded maximum nesting depth"},
+	}
+	for _, tt := range tests {
+		m, err := proto.DecodeWire([]byte(tt.in))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("DecodeWire(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+		}
+		if len(m) != tt.wantLen {
+			t.Errorf("DecodeWire(%q) returned %d fields, want %d", tt.in, len(m), tt.wantLen)
+		}
+	}
+}
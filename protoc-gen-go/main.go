@@ -29,6 +29,7 @@ import (
 	"strings"
 
 	"github.com/golang/protobuf/internal/gengogrpc"
+	"github.com/golang/protobuf/internal/genvalidate"
 	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
 	"google.golang.org/protobuf/compiler/protogen"
 )
@@ -36,7 +37,7 @@ import (
 func main() {
 	var (
 		flags        flag.FlagSet
-		plugins      = flags.String("plugins", "", "list of plugins to enable (supported values: grpc)")
+		plugins      = flags.String("plugins", "", "list of plugins to enable (supported values: grpc, validate)")
 		importPrefix = flags.String("import_prefix", "", "prefix to prepend to import paths")
 	)
 	importRewriteFunc := func(importPath protogen.GoImportPath) protogen.GoImportPath {
@@ -53,11 +54,13 @@ func main() {
 		ParamFunc:         flags.Set,
 		ImportRewriteFunc: importRewriteFunc,
 	}.Run(func(gen *protogen.Plugin) error {
-		grpc := false
+		grpc, validate := false, false
 		for _, plugin := range strings.Split(*plugins, ",") {
 			switch plugin {
 			case "grpc":
 				grpc = true
+			case "validate":
+				validate = true
 			case "":
 			default:
 				return fmt.Errorf("protoc-gen-go: unknown plugin %q", plugin)
@@ -71,6 +74,11 @@ func main() {
 			if grpc {
 				gengogrpc.GenerateFileContent(gen, f, g)
 			}
+			if validate {
+				if err := genvalidate.GenerateFileContent(gen, f, g); err != nil {
+					return err
+				}
+			}
 		}
 		gen.SupportedFeatures = gengo.SupportedFeatures
 		return nil
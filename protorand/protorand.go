@@ -0,0 +1,337 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protorand generates random messages for property-based tests and
+// fuzzing.
+//
+// Generated messages populate a random subset of the fields of each message,
+// respecting the constraints of the schema: at most one field of each oneof
+// is populated, required fields are always populated, and values of closed
+// enums are defined values. Messages are nested up to a maximum depth, so
+// that recursive message types produce finite messages.
+//
+// For example, a property-based test of marshaling may look like:
+//
+//	g := protorand.New(1)
+//	for i := 0; i < 1000; i++ {
+//		m := new(foopb.Foo)
+//		g.Fill(m)
+//		b, err := proto.Marshal(m)
+//		...
+//	}
+//
+// A Generator created by FromBytes draws its randomness from a byte slice,
+// which makes it suitable for use with fuzz tests:
+//
+//	func FuzzFoo(f *testing.F) {
+//		f.Fuzz(func(t *testing.T, data []byte) {
+//			m := new(foopb.Foo)
+//			protorand.FromBytes(data).Fill(m)
+//			...
+//		})
+//	}
+package protorand
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	// DefaultMaxDepth is the default maximum nesting depth of messages.
+	DefaultMaxDepth = 4
+
+	// DefaultMaxLen is the default maximum number of elements of repeated
+	// fields, entries of map fields, and length of strings and bytes.
+	DefaultMaxLen = 8
+)
+
+// maxRequiredDepth is the depth at which generation gives up on populating
+// required message fields, which a schema may declare recursively.
+const maxRequiredDepth = 100
+
+// Generator generates random messages.
+// The zero value is a Generator that behaves as one returned by New(0).
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	// MaxDepth is the maximum nesting depth of messages, where the fields of
+	// the generated message itself are at depth 1. Message fields at that
+	// depth are not populated, unless they are required.
+	// If zero, DefaultMaxDepth is used.
+	MaxDepth int
+
+	// MaxLen is the maximum number of elements of repeated fields, entries
+	// of map fields, and length of strings (in runes) and bytes.
+	// If zero, DefaultMaxLen is used.
+	MaxLen int
+
+	// Extensions specifies whether to populate the extension fields of
+	// messages that are registered in the global registry.
+	Extensions bool
+
+	rand *rand.Rand
+}
+
+// New returns a Generator that produces the same sequence of messages
+// for the same seed.
+func New(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// FromBytes returns a Generator that draws its randomness from b.
+// Once b is exhausted, the randomness is drawn from a source seeded with
+// the length of b, so that a short b still produces varied messages.
+// Similar inputs produce similar messages, which lets fuzzing explore
+// messages by mutating b.
+func FromBytes(b []byte) *Generator {
+	return &Generator{rand: rand.New(&bytesSource{b: b, rest: rand.NewSource(int64(len(b)))})}
+}
+
+type bytesSource struct {
+	b    []byte
+	rest rand.Source
+}
+
+func (s *bytesSource) Int63() int64 {
+	return int64(s.Uint64() & math.MaxInt64)
+}
+
+func (s *bytesSource) Uint64() uint64 {
+	if len(s.b) == 0 {
+		return uint64(s.rest.Int63())<<1 ^ uint64(s.rest.Int63())
+	}
+	var x [8]byte
+	n := copy(x[:], s.b)
+	s.b = s.b[n:]
+	return binary.LittleEndian.Uint64(x[:])
+}
+
+func (s *bytesSource) Seed(seed int64) {
+	s.b = nil
+	s.rest.Seed(seed)
+}
+
+// Fill resets m and populates it with random values.
+func (g *Generator) Fill(m proto.Message) {
+	m.Reset()
+	mr := proto.MessageReflect(m)
+	g.init()
+	g.fillMessage(mr, 1)
+}
+
+// Message returns a new message of the type described by md populated with
+// random values. The message has the type registered for md in the global
+// registry, if any, or is otherwise a dynamic message.
+func (g *Generator) Message(md protoreflect.MessageDescriptor) proto.Message {
+	var m protoreflect.Message
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err == nil && mt.Descriptor() == md {
+		m = mt.New()
+	} else {
+		m = dynamicpb.NewMessage(md)
+	}
+	g.init()
+	g.fillMessage(m, 1)
+	return proto.MessageV1(m.Interface())
+}
+
+// init seeds the source of randomness of a zero Generator.
+func (g *Generator) init() {
+	if g.rand == nil {
+		g.rand = rand.New(rand.NewSource(0))
+	}
+}
+
+func (g *Generator) maxDepth() int {
+	if g.MaxDepth > 0 {
+		return g.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+func (g *Generator) maxLen() int {
+	if g.MaxLen > 0 {
+		return g.MaxLen
+	}
+	return DefaultMaxLen
+}
+
+// fillMessage populates the fields of m, which is at the given depth.
+func (g *Generator) fillMessage(m protoreflect.Message, depth int) {
+	md := m.Descriptor()
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+			if od.Fields().Get(0) == fd {
+				g.fillOneof(m, od, depth)
+			}
+			continue
+		}
+		if fd.Cardinality() == protoreflect.Required || g.rand.Intn(2) == 0 {
+			g.fillField(m, fd, depth)
+		}
+	}
+	if g.Extensions && md.ExtensionRanges().Len() > 0 {
+		var xts []protoreflect.ExtensionType
+		protoregistry.GlobalTypes.RangeExtensionsByMessage(md.FullName(), func(xt protoreflect.ExtensionType) bool {
+			xts = append(xts, xt)
+			return true
+		})
+		sort.Slice(xts, func(i, j int) bool {
+			return xts[i].TypeDescriptor().Number() < xts[j].TypeDescriptor().Number()
+		})
+		for _, xt := range xts {
+			if g.rand.Intn(2) == 0 {
+				g.fillField(m, xt.TypeDescriptor(), depth)
+			}
+		}
+	}
+}
+
+// fillOneof populates one of the fields of od, or none.
+func (g *Generator) fillOneof(m protoreflect.Message, od protoreflect.OneofDescriptor, depth int) {
+	i := g.rand.Intn(od.Fields().Len() + 1)
+	if i < od.Fields().Len() {
+		g.fillField(m, od.Fields().Get(i), depth)
+	}
+}
+
+func (g *Generator) fillField(m protoreflect.Message, fd protoreflect.FieldDescriptor, depth int) {
+	hasMessages := fd.Message() != nil && (!fd.IsMap() || fd.MapValue().Message() != nil)
+	if hasMessages && depth >= g.maxDepth() && (fd.Cardinality() != protoreflect.Required || depth >= maxRequiredDepth) {
+		return
+	}
+	switch {
+	case fd.IsList():
+		list := m.Mutable(fd).List()
+		for n := g.rand.Intn(g.maxLen() + 1); n > 0; n-- {
+			list.Append(g.value(list.NewElement, fd, depth))
+		}
+		if list.Len() == 0 {
+			m.Clear(fd)
+		}
+	case fd.IsMap():
+		mp := m.Mutable(fd).Map()
+		for n := g.rand.Intn(g.maxLen() + 1); n > 0; n-- {
+			k := g.value(nil, fd.MapKey(), depth).MapKey()
+			mp.Set(k, g.value(mp.NewValue, fd.MapValue(), depth))
+		}
+		if mp.Len() == 0 {
+			m.Clear(fd)
+		}
+	default:
+		m.Set(fd, g.value(func() protoreflect.Value { return m.NewField(fd) }, fd, depth))
+	}
+}
+
+// value returns a random value of fd, which is a new message obtained
+// from newValue if fd is a message field.
+func (g *Generator) value(newValue func() protoreflect.Value, fd protoreflect.FieldDescriptor, depth int) protoreflect.Value {
+	r := g.rand
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(r.Intn(2) == 0)
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		if !fd.Enum().IsClosed() && r.Intn(8) == 0 {
+			// Open enums may hold undefined values.
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(g.int64(32)))
+		}
+		return protoreflect.ValueOfEnum(values.Get(r.Intn(values.Len())).Number())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(g.int64(32)))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(g.int64(64))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(g.uint64(32)))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(g.uint64(64))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(g.float64()))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(g.float64())
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(g.string())
+	case protoreflect.BytesKind:
+		b := make([]byte, r.Intn(g.maxLen()+1))
+		r.Read(b)
+		return protoreflect.ValueOfBytes(b)
+	default:
+		v := newValue()
+		g.fillMessage(v.Message(), depth+1)
+		return v
+	}
+}
+
+// int64 returns a random integer of the given bit size, which is likely to
+// be small or an extreme value.
+func (g *Generator) int64(bits uint) int64 {
+	r := g.rand
+	min, max := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1
+	switch r.Intn(8) {
+	case 0:
+		return []int64{0, 1, -1, min, max}[r.Intn(5)]
+	case 1, 2, 3:
+		return r.Int63n(201) - 100
+	default:
+		return int64(r.Uint64()) >> (64 - bits)
+	}
+}
+
+// uint64 is like int64, but returns an unsigned integer.
+func (g *Generator) uint64(bits uint) uint64 {
+	r := g.rand
+	max := uint64(1)<<bits - 1
+	if bits == 64 {
+		max = math.MaxUint64
+	}
+	switch r.Intn(8) {
+	case 0:
+		return []uint64{0, 1, max}[r.Intn(3)]
+	case 1, 2, 3:
+		return uint64(r.Intn(201))
+	default:
+		return r.Uint64() & max
+	}
+}
+
+// float64 returns a random floating-point number, which may be a special
+// value such as an infinity, NaN, or negative zero.
+func (g *Generator) float64() float64 {
+	r := g.rand
+	switch r.Intn(8) {
+	case 0:
+		return []float64{0, math.Copysign(0, -1), 1, -1, math.Inf(1), math.Inf(-1), math.NaN(), math.MaxFloat64, math.SmallestNonzeroFloat64}[r.Intn(9)]
+	case 1, 2, 3:
+		return float64(r.Intn(201)-100) / 4
+	default:
+		return r.NormFloat64() * math.Pow(10, float64(r.Intn(21)-10))
+	}
+}
+
+// string returns a random valid UTF-8 string.
+func (g *Generator) string() string {
+	r := g.rand
+	rs := make([]rune, r.Intn(g.maxLen()+1))
+	for i := range rs {
+		switch r.Intn(4) {
+		case 0:
+			rs[i] = rune(r.Intn(utf8.MaxRune + 1))
+			if !utf8.ValidRune(rs[i]) {
+				rs[i] = utf8.RuneError
+			}
+		default:
+			rs[i] = rune(' ' + r.Intn('~'-' '+1))
+		}
+	}
+	return string(rs)
+}
@@ -0,0 +1,305 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protorand_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protorand"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb2 "github.com/golang/protobuf/internal/testprotos/proto2_proto"
+	pb3 "github.com/golang/protobuf/internal/testprotos/proto3_proto"
+)
+
+var testMessages = []proto.Message{
+	(*pb2.GoTest)(nil),
+	(*pb2.MyMessage)(nil),
+	(*pb2.MessageWithMap)(nil),
+	(*pb2.Oneof)(nil),
+	(*pb2.Communique)(nil),
+	(*pb2.MoreRepeated)(nil),
+	(*pb3.Message)(nil),
+	(*pb3.IntMaps)(nil),
+}
+
+func descriptorOf(m proto.Message) protoreflect.MessageDescriptor {
+	return proto.MessageReflect(m).Descriptor()
+}
+
+func TestRoundTrip(t *testing.T) {
+	g := protorand.New(1)
+	g.Extensions = true
+	for _, tm := range testMessages {
+		for i := 0; i < 100; i++ {
+			m := g.Message(descriptorOf(tm))
+			b, err := proto.Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal(%v) error: %v", m, err)
+			}
+			got := proto.Clone(m)
+			if err := proto.Unmarshal(b, got); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if !proto.Equal(got, m) {
+				t.Fatalf("Unmarshal(Marshal(m)) != m:\ngot:  %v\nwant: %v", got, m)
+			}
+			if proto.Hash(got) != proto.Hash(m) {
+				t.Fatalf("Hash(Unmarshal(Marshal(m))) != Hash(m) for %v", m)
+			}
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	// Merging messages is equivalent to concatenating their encodings.
+	g := protorand.New(2)
+	for _, tm := range testMessages {
+		md := descriptorOf(tm)
+		for i := 0; i < 100; i++ {
+			x, y := g.Message(md), g.Message(md)
+			clearNegativeZero(x)
+			clearNegativeZero(y)
+			bx, err := proto.Marshal(x)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			by, err := proto.Marshal(y)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			want := proto.Clone(x)
+			if err := proto.Unmarshal(append(bx, by...), want); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			got := proto.Clone(x)
+			proto.Merge(got, y)
+			if !proto.Equal(got, want) {
+				t.Fatalf("Merge(%v, %v):\ngot:  %v\nwant: %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+// clearNegativeZero clears the fields without presence that hold negative zero,
+// which Merge and Clone do not copy because the value compares equal to zero.
+func clearNegativeZero(m proto.Message) {
+	proto.Walk(m, func(v *proto.FieldValue) error {
+		if v.Field.HasPresence() || v.Field.IsList() || v.Field.IsMap() {
+			return nil
+		}
+		switch v.Field.Kind() {
+		case protoreflect.FloatKind, protoreflect.DoubleKind:
+			if f := v.Value.Float(); f == 0 && math.Signbit(f) {
+				v.Clear()
+			}
+		}
+		return nil
+	})
+}
+
+func TestDeterministic(t *testing.T) {
+	for _, newGenerator := range []func() *protorand.Generator{
+		func() *protorand.Generator { return protorand.New(3) },
+		func() *protorand.Generator { return protorand.FromBytes([]byte("some fuzz input")) },
+	} {
+		g1, g2 := newGenerator(), newGenerator()
+		for i := 0; i < 20; i++ {
+			m1, m2 := new(pb2.MyMessage), new(pb2.MyMessage)
+			g1.Fill(m1)
+			g2.Fill(m2)
+			b1, err1 := proto.MarshalCanonical(m1)
+			b2, err2 := proto.MarshalCanonical(m2)
+			if err1 != nil || err2 != nil || !bytes.Equal(b1, b2) {
+				t.Fatalf("generators with the same input produced different messages:\n%v\n%v", m1, m2)
+			}
+		}
+	}
+
+	m1, m2 := new(pb3.Message), new(pb3.Message)
+	for i := 0; proto.Equal(m1, m2); i++ {
+		if i == 10 {
+			t.Fatalf("generators with different seeds produced equal messages")
+		}
+		protorand.New(int64(2 * i)).Fill(m1)
+		protorand.New(int64(2*i + 1)).Fill(m2)
+	}
+}
+
+func TestZeroGenerator(t *testing.T) {
+	// The zero Generator is usable and seeded as New(0).
+	g1, g2 := &protorand.Generator{MaxDepth: 2}, protorand.New(0)
+	g2.MaxDepth = 2
+	for i := 0; i < 5; i++ {
+		m1, m2 := new(pb2.MyMessage), new(pb2.MyMessage)
+		g1.Fill(m1)
+		g2.Fill(m2)
+		if !proto.Equal(m1, m2) {
+			t.Fatalf("zero Generator and New(0) produced different messages:\n%v\n%v", m1, m2)
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	g := protorand.New(4)
+	g.MaxDepth = 2
+	var maxDepth int
+	for i := 0; i < 100; i++ {
+		m := new(pb3.Message)
+		g.Fill(m)
+		if d := depth(proto.MessageReflect(m)); d > maxDepth {
+			maxDepth = d
+		}
+	}
+	if maxDepth != 2 {
+		t.Errorf("maximum depth of generated messages = %d, want 2", maxDepth)
+	}
+}
+
+// depth returns the depth of the most deeply nested populated field of m.
+func depth(m protoreflect.Message) int {
+	var max int
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		d := 1
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				if n := 1 + depth(v.List().Get(i).Message()); n > d {
+					d = n
+				}
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				if n := 1 + depth(v.Message()); n > d {
+					d = n
+				}
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			d = 1 + depth(v.Message())
+		}
+		if d > max {
+			max = d
+		}
+		return true
+	})
+	return max
+}
+
+func TestMaxLen(t *testing.T) {
+	g := protorand.New(5)
+	g.MaxLen = 2
+	var sawMax bool
+	for i := 0; i < 100; i++ {
+		m := new(pb3.Message)
+		g.Fill(m)
+		proto.Walk(m, func(v *proto.FieldValue) error {
+			if v.Index > 1 {
+				t.Fatalf("generated %s with more than 2 elements", v.Path())
+			}
+			sawMax = sawMax || v.Index == 1
+			if v.Field.Kind() == protoreflect.StringKind && len([]rune(v.Value.String())) > 2 {
+				t.Fatalf("generated %s = %q, longer than 2", v.Path(), v.Value.String())
+			}
+			return nil
+		})
+		if len(m.Terrain) > 2 {
+			t.Fatalf("generated terrain with %d entries", len(m.Terrain))
+		}
+	}
+	if !sawMax {
+		t.Errorf("no repeated field with 2 elements was generated")
+	}
+}
+
+func TestRequiredAndOneof(t *testing.T) {
+	g := protorand.New(6)
+	seen := make(map[protoreflect.Name]bool)
+	for i := 0; i < 200; i++ {
+		m := new(pb2.Communique)
+		g.Fill(m)
+		if err := protoV2.CheckInitialized(proto.MessageV2(m)); err != nil {
+			t.Fatalf("CheckInitialized(%v) error: %v", m, err)
+		}
+		if fd := proto.MessageReflect(m).WhichOneof(descriptorOf(m).Oneofs().ByName("union")); fd != nil {
+			seen[fd.Name()] = true
+		}
+		gt := new(pb2.GoTest)
+		g.Fill(gt)
+		if err := protoV2.CheckInitialized(proto.MessageV2(gt)); err != nil {
+			t.Fatalf("CheckInitialized(%v) error: %v", gt, err)
+		}
+	}
+	if n := descriptorOf(&pb2.Communique{}).Oneofs().ByName("union").Fields().Len(); len(seen) != n {
+		t.Errorf("populated %d of %d oneof fields: %v", len(seen), n, seen)
+	}
+}
+
+func TestClosedEnums(t *testing.T) {
+	g := protorand.New(7)
+	for i := 0; i < 200; i++ {
+		m := new(pb2.MyMessage)
+		g.Fill(m)
+		if m.Bikeshed != nil && pb2.MyMessage_Color_name[int32(*m.Bikeshed)] == "" {
+			t.Fatalf("generated undefined value %d of closed enum", *m.Bikeshed)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	md := descriptorOf(&pb3.Message{})
+	m := protorand.New(8).Message(md)
+	if _, ok := m.(*pb3.Message); !ok {
+		t.Errorf("Message(%v) has type %T, want *pb3.Message", md.FullName(), m)
+	}
+
+	// Filling a dynamic message draws the same values as the generated type.
+	dyn := proto.MessageV1(dynamicpb.NewMessage(md))
+	protorand.New(8).Fill(dyn)
+	if !proto.Equal(dyn, toDynamic(t, m)) {
+		t.Errorf("Fill of dynamic message:\ngot:  %v\nwant: %v", dyn, m)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	g := protorand.New(9)
+	g.Extensions = true
+	var n int
+	for i := 0; i < 20; i++ {
+		m := new(pb2.MyMessage)
+		g.Fill(m)
+		if proto.HasExtension(m, pb2.E_Ext_Text) {
+			n++
+		}
+	}
+	if n == 0 {
+		t.Errorf("no extension was populated")
+	}
+	m := new(pb2.MyMessage)
+	protorand.New(9).Fill(m)
+	for _, xt := range proto.RegisteredExtensions(m) {
+		if proto.HasExtension(m, xt) {
+			t.Errorf("extension %v populated without Extensions", xt.Name)
+		}
+	}
+}
+
+func toDynamic(t *testing.T, m proto.Message) proto.Message {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	dyn := proto.MessageV1(dynamicpb.NewMessage(descriptorOf(m)))
+	if err := proto.Unmarshal(b, dyn); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	return dyn
+}
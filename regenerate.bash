@@ -6,4 +6,5 @@
 cd "$(git rev-parse --show-toplevel)"
 set -e
 go run ./internal/cmd/generate-alias -execute
+protoc --go_out=paths=source_relative:. validate/rules/rules.proto
 go test ./protoc-gen-go -regenerate
@@ -0,0 +1,319 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package validate validates messages against rules declared in the
+// (golang.protobuf.validate.rules) option of their fields,
+// as defined in the "github.com/golang/protobuf/validate/rules" package.
+//
+// For example, given:
+//
+//	import "validate/rules/rules.proto";
+//
+//	message Person {
+//	  string name = 1 [(golang.protobuf.validate.rules) = {required: true, max_len: 64}];
+//	  repeated string emails = 2 [(golang.protobuf.validate.rules) = {max_items: 4, pattern: "@"}];
+//	}
+//
+// Validate reports a violation for a Person without a name, with a name longer
+// than 64 characters, or with more than 4 emails or an email without an @.
+//
+// Validate uses protobuf reflection to check the rules. Running protoc-gen-go
+// with the validate plugin (--go_opt=plugins=validate) additionally generates
+// a Validate method for each message that checks the same rules without
+// reflection, which Validate uses when it is present.
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/validate/rules"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Violation is a field value that does not satisfy a validation rule.
+type Violation struct {
+	// Path is the path to the field from the validated message, such as
+	// `friends[2].address.city` or `labels["env"]`.
+	// Extension fields are referred to by their full name in parentheses.
+	Path string
+
+	// Rule is the name of the violated rule, such as "max_len".
+	Rule string
+
+	// Message describes the violation.
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Error is the error reported by Validate, which lists all violations
+// of validation rules in the message.
+//
+// Violations are listed in the order of the fields in the message
+// declarations, except that violations within map values are listed in
+// an unspecified order.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString("validate: ")
+	for i, v := range e.Violations {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(v.String())
+	}
+	return sb.String()
+}
+
+// Err returns e if it has any violations, or nil otherwise.
+func (e *Error) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Append adds a violation of rule by the value got of the field at path,
+// where limit is the value of the rule (e.g., the maximum for "max").
+// It is used by generated Validate methods.
+func (e *Error) Append(path, rule string, got, limit interface{}) {
+	var msg string
+	switch rule {
+	case "required":
+		msg = "is required"
+	case "min":
+		msg = fmt.Sprintf("%v is less than minimum %v", got, limit)
+	case "max":
+		msg = fmt.Sprintf("%v is greater than maximum %v", got, limit)
+	case "min_len":
+		msg = fmt.Sprintf("length %v is less than minimum %v", got, limit)
+	case "max_len":
+		msg = fmt.Sprintf("length %v is greater than maximum %v", got, limit)
+	case "pattern":
+		msg = fmt.Sprintf("%q does not match pattern %q", got, limit)
+	case "defined_only":
+		msg = fmt.Sprintf("%v is not a defined enum value", got)
+	case "min_items":
+		msg = fmt.Sprintf("%v items is less than minimum %v", got, limit)
+	case "max_items":
+		msg = fmt.Sprintf("%v items is greater than maximum %v", got, limit)
+	default:
+		msg = fmt.Sprintf("violates %s rule", rule)
+	}
+	e.Violations = append(e.Violations, Violation{Path: path, Rule: rule, Message: msg})
+}
+
+// AppendNested validates m, which is the value of the field at path,
+// and adds its violations with their paths prefixed by path.
+// It is used by generated Validate methods.
+func (e *Error) AppendNested(path string, m proto.Message) {
+	switch err := Validate(m).(type) {
+	case nil:
+	case *Error:
+		for _, v := range err.Violations {
+			v.Path = path + "." + v.Path
+			e.Violations = append(e.Violations, v)
+		}
+	default:
+		// A hand-written Validate method reported an error of its own.
+		e.Violations = append(e.Violations, Violation{Path: path, Message: err.Error()})
+	}
+}
+
+// Validate checks m and all messages embedded in it against the validation
+// rules of their fields. If there are violations, it reports an *Error
+// listing all of them.
+//
+// If m has a Validate method, such as one generated by protoc-gen-go with
+// the validate plugin, Validate returns its result instead.
+// Generated Validate methods do not check extension fields.
+func Validate(m proto.Message) error {
+	if v, ok := m.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	if m == nil {
+		return nil
+	}
+	var e Error
+	validateMessage(&e, proto.MessageReflect(m))
+	return e.Err()
+}
+
+func validateMessage(e *Error, m protoreflect.Message) {
+	if !m.IsValid() {
+		return
+	}
+	fds := m.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		validateField(e, m, fd, string(fd.Name()))
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fd.IsExtension() {
+			validateField(e, m, fd, "("+string(fd.FullName())+")")
+		}
+		return true
+	})
+}
+
+func validateField(e *Error, m protoreflect.Message, fd protoreflect.FieldDescriptor, path string) {
+	r := fieldRules(fd)
+	if r == nil {
+		if fd.Message() == nil {
+			return
+		}
+		r = noRules
+	}
+	has := m.Has(fd)
+	if r.GetRequired() && !has {
+		e.Append(path, "required", nil, nil)
+	}
+	switch {
+	case fd.IsList():
+		list := m.Get(fd).List()
+		validateItems(e, path, list.Len(), r)
+		for i := 0; i < list.Len(); i++ {
+			validateValue(e, fmt.Sprintf("%s[%d]", path, i), fd, list.Get(i), r)
+		}
+	case fd.IsMap():
+		mp := m.Get(fd).Map()
+		validateItems(e, path, mp.Len(), r)
+		format := "%s[%v]"
+		if fd.MapKey().Kind() == protoreflect.StringKind {
+			format = "%s[%q]"
+		}
+		// Sort the keys for a deterministic order of the violations.
+		for _, k := range sortedMapKeys(fd, mp) {
+			validateValue(e, fmt.Sprintf(format, path, k.Interface()), fd.MapValue(), mp.Get(k), r)
+		}
+	case has || !fd.HasPresence():
+		validateValue(e, path, fd, m.Get(fd), r)
+	}
+}
+
+// sortedMapKeys returns the keys of the map field fd in order.
+func sortedMapKeys(fd protoreflect.FieldDescriptor, mp protoreflect.Map) []protoreflect.MapKey {
+	keys := make([]protoreflect.MapKey, 0, mp.Len())
+	mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		switch fd.MapKey().Kind() {
+		case protoreflect.BoolKind:
+			return !keys[i].Bool() && keys[j].Bool()
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			return keys[i].Int() < keys[j].Int()
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			return keys[i].Uint() < keys[j].Uint()
+		default:
+			return keys[i].String() < keys[j].String()
+		}
+	})
+	return keys
+}
+
+func validateItems(e *Error, path string, n int, r *rules.FieldRules) {
+	if r.MinItems != nil && uint64(n) < r.GetMinItems() {
+		e.Append(path, "min_items", n, r.GetMinItems())
+	}
+	if r.MaxItems != nil && uint64(n) > r.GetMaxItems() {
+		e.Append(path, "max_items", n, r.GetMaxItems())
+	}
+}
+
+// validateValue checks a single value of the field fd,
+// which is an element of a list or a value of a map if fd is repeated.
+func validateValue(e *Error, path string, fd protoreflect.FieldDescriptor, v protoreflect.Value, r *rules.FieldRules) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		e.AppendNested(path, proto.MessageV1(v.Message().Interface()))
+	case protoreflect.StringKind:
+		s := v.String()
+		if n := utf8.RuneCountInString(s); r.MinLen != nil && uint64(n) < r.GetMinLen() {
+			e.Append(path, "min_len", n, r.GetMinLen())
+		} else if r.MaxLen != nil && uint64(n) > r.GetMaxLen() {
+			e.Append(path, "max_len", n, r.GetMaxLen())
+		}
+		if r.Pattern != nil {
+			re, err := compilePattern(r.GetPattern())
+			if err != nil {
+				e.Violations = append(e.Violations, Violation{Path: path, Rule: "pattern", Message: err.Error()})
+			} else if !re.MatchString(s) {
+				e.Append(path, "pattern", s, r.GetPattern())
+			}
+		}
+	case protoreflect.BytesKind:
+		if n := len(v.Bytes()); r.MinLen != nil && uint64(n) < r.GetMinLen() {
+			e.Append(path, "min_len", n, r.GetMinLen())
+		} else if r.MaxLen != nil && uint64(n) > r.GetMaxLen() {
+			e.Append(path, "max_len", n, r.GetMaxLen())
+		}
+	case protoreflect.EnumKind:
+		n := v.Enum()
+		if r.GetDefinedOnly() && fd.Enum().Values().ByNumber(n) == nil {
+			e.Append(path, "defined_only", int32(n), nil)
+		}
+	case protoreflect.BoolKind:
+	default:
+		var f float64
+		switch x := v.Interface().(type) {
+		case int32:
+			f = float64(x)
+		case int64:
+			f = float64(x)
+		case uint32:
+			f = float64(x)
+		case uint64:
+			f = float64(x)
+		case float32:
+			f = float64(x)
+		case float64:
+			f = x
+		}
+		if r.Min != nil && f < r.GetMin() {
+			e.Append(path, "min", v.Interface(), r.GetMin())
+		}
+		if r.Max != nil && f > r.GetMax() {
+			e.Append(path, "max", v.Interface(), r.GetMax())
+		}
+	}
+}
+
+var noRules = new(rules.FieldRules)
+
+// fieldRules returns the validation rules of fd, or nil if it has none.
+func fieldRules(fd protoreflect.FieldDescriptor) *rules.FieldRules {
+	opts := fd.Options()
+	if opts == nil || !protoV2.HasExtension(opts, rules.E_Rules) {
+		return nil
+	}
+	return protoV2.GetExtension(opts, rules.E_Rules).(*rules.FieldRules)
+}
+
+var patterns sync.Map // map[string]*regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %v", err)
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
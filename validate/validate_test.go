@@ -0,0 +1,181 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/validate"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb "github.com/golang/protobuf/internal/testprotos/validate_proto"
+)
+
+// violation is a Violation without its message.
+type violation struct{ Path, Rule string }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc string
+		in   proto.Message
+		want []violation
+	}{{
+		desc: "valid",
+		in: &pb.Person{
+			Name:    "Ann",
+			Age:     30,
+			Email:   "ann@example.com",
+			Color:   pb.Color_RED,
+			Tags:    []string{"x"},
+			Address: &pb.Address{City: "Zurich", Zip: 8000},
+			Homes:   map[string]*pb.Address{"summer": {City: "Bern"}},
+			Contact: &pb.Person_Phone{Phone: "12345"},
+			Score:   proto.Float64(1),
+			Palette: []pb.Color{pb.Color_GREEN},
+			Counts:  map[int32]uint64{1: 10},
+		},
+	}, {
+		desc: "empty",
+		in:   &pb.Person{},
+		want: []violation{
+			{"name", "required"},
+			{"email", "pattern"},
+			{"address", "required"},
+		},
+	}, {
+		desc: "invalid",
+		in: &pb.Person{
+			Name:    "Bartholomew",
+			Age:     200,
+			Email:   "nope",
+			Color:   99,
+			Tags:    []string{"a", "", "b", "c"},
+			Address: &pb.Address{},
+			Friends: []*pb.Person{
+				{Name: "Cy", Email: "cy@example.com", Address: &pb.Address{City: "Basel"}},
+				{Name: "Di", Email: "di@example.com", Address: &pb.Address{City: "Chur", Zip: 100000}},
+			},
+			Homes:   map[string]*pb.Address{"winter": {}, "autumn": {}},
+			Contact: &pb.Person_Office{Office: &pb.Address{}},
+			Score:   proto.Float64(-1),
+			Avatar:  []byte("large"),
+			Palette: []pb.Color{pb.Color_RED, 7},
+			Counts:  map[int32]uint64{-1: 11, 12: 13, -20: 30, 3: 4, 0: 50, 7: 70},
+		},
+		// Map entries are reported in order of their keys.
+		want: []violation{
+			{"name", "max_len"},
+			{"age", "max"},
+			{"email", "pattern"},
+			{"color", "defined_only"},
+			{"tags", "max_items"},
+			{"tags[1]", "min_len"},
+			{"address.city", "required"},
+			{"friends[1].address.zip", "max"},
+			{`homes["autumn"].city`, "required"},
+			{`homes["winter"].city`, "required"},
+			{"office.city", "required"},
+			{"score", "min"},
+			{"avatar", "max_len"},
+			{"palette[1]", "defined_only"},
+			{"counts[-20]", "max"},
+			{"counts[-1]", "max"},
+			{"counts[0]", "max"},
+			{"counts[7]", "max"},
+			{"counts[12]", "max"},
+		},
+	}, {
+		desc: "phone",
+		in:   &pb.Person{Name: "Ed", Email: "ed@example.com", Address: &pb.Address{City: "Genf"}, Contact: &pb.Person_Phone{Phone: "911"}},
+		want: []violation{{"phone", "min_len"}},
+	}, {
+		desc: "presence unset",
+		in:   &pb.Legacy{},
+		want: []violation{
+			{"id", "required"},
+			{"ids", "required"},
+			{"blob", "required"},
+		},
+	}, {
+		desc: "presence set",
+		in: &pb.Legacy{
+			Id:    proto.String("a"),
+			Color: pb.Color(5).Enum(),
+			Delta: proto.Int64(-6),
+			Ids:   []uint32{1, 101},
+			Blob:  []byte{},
+		},
+		want: []violation{
+			{"id", "min_len"},
+			{"color", "defined_only"},
+			{"delta", "min"},
+			{"ids[1]", "max"},
+		},
+	}}
+
+	for _, tt := range tests {
+		// Check both the generated Validate method and protobuf reflection,
+		// using a dynamic message that has no Validate method.
+		b, err := proto.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("%s: Marshal error: %v", tt.desc, err)
+		}
+		dyn := dynamicpb.NewMessage(proto.MessageReflect(tt.in).Descriptor())
+		if err := proto.Unmarshal(b, proto.MessageV1(dyn)); err != nil {
+			t.Fatalf("%s: Unmarshal error: %v", tt.desc, err)
+		}
+		for _, m := range []proto.Message{tt.in, proto.MessageV1(dyn)} {
+			err := validate.Validate(m)
+			var got []violation
+			var msgs []string
+			if err != nil {
+				verr, ok := err.(*validate.Error)
+				if !ok {
+					t.Fatalf("%s: Validate(%T) error = %v, want *validate.Error", tt.desc, m, err)
+				}
+				for _, v := range verr.Violations {
+					got = append(got, violation{v.Path, v.Rule})
+					msgs = append(msgs, v.Message)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s: Validate(%T) mismatch (-want +got):\n%s", tt.desc, m, diff)
+			}
+			for _, msg := range msgs {
+				if msg == "" {
+					t.Errorf("%s: Validate(%T) reported violation without message", tt.desc, m)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateMessages(t *testing.T) {
+	p := &pb.Person{Name: "Ann", Age: -1, Email: "a@b", Tags: []string{"x", "y", "z", "w"}, Address: &pb.Address{City: "Bern"}}
+	dyn := dynamicpb.NewMessage(proto.MessageReflect(p).Descriptor())
+	b, err := proto.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if err := proto.Unmarshal(b, proto.MessageV1(dyn)); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	const want = "validate: age: -1 is less than minimum 0; tags: 4 items is greater than maximum 3"
+	for _, m := range []proto.Message{p, proto.MessageV1(dyn)} {
+		if err := validate.Validate(m); err == nil || err.Error() != want {
+			t.Errorf("Validate(%T) = %v, want %v", m, err, want)
+		}
+	}
+}
+
+func TestValidateNil(t *testing.T) {
+	for _, m := range []proto.Message{nil, (*pb.Person)(nil)} {
+		if err := validate.Validate(m); err != nil {
+			t.Errorf("Validate(%#v) = %v, want nil", m, err)
+		}
+	}
+}